@@ -1,53 +1,148 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"go-game-space-shooter/internal/assets"
+	"go-game-space-shooter/internal/config"
 	"go-game-space-shooter/internal/game"
-	"path"
-	"runtime"
-	"strconv"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/joho/godotenv"
 )
 
 const VERSION = "v1.2.0"
 const WINDOW_TITLE = "Space Shooter in Go! - by Simão Gomes at simaogomes.com"
 
 func main() {
-	var Configs map[string]string
-	Configs, err := godotenv.Read(path.Join(getRuntimeDirectory(), "..", "..", "configs/configs.env"))
+	replayPath := flag.String("replay", "", "path to a replay file to play back")
+	verifyReplay := flag.Bool("verify", false, "verify the replay without opening a window, instead of playing it back")
+	configPath := flag.String("config", "", "path to the configs file (by default, it's looked for in the user's config folder, then in the \"configs\" folder next to the executable)")
+	printConfig := flag.Bool("print-config", false, "print the configs the game would run with, and exit")
+	configFlags := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	// Find the configs file (the embedded one, if there's none)
+	source, err := config.Find(*configPath)
 	if err != nil {
-		panic(err)
+		fmt.Println("Invalid configs:", err)
+		os.Exit(1)
 	}
 
-	// Config: Window Width
-	window_width, err := strconv.Atoi(Configs["WINDOW_WIDTH"])
+	// Configs: Defaults, then the configs file, the environment and the flags
+	Configs, err := config.Load(source, configFlags)
 	if err != nil {
-		panic(err)
+		fmt.Println("Invalid configs in "+source.GetPath()+":", err)
+		os.Exit(1)
+	}
+
+	// Print the configs, in the configs file format
+	if *printConfig {
+		fmt.Println("# Loaded from " + source.GetPath())
+		err = Configs.Print(os.Stdout)
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	// Load the replay to play back
+	var replay *game.Replay
+	if *replayPath != "" {
+		replay, err = game.LoadReplay(*replayPath)
+		if err != nil {
+			fmt.Println("Invalid replay:", err)
+			os.Exit(1)
+		}
+
+		// Verify the replay reaches the recorded score and wave
+		if *verifyReplay {
+			err = replay.Verify()
+			if err != nil {
+				fmt.Println("Replay verification failed:", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Replay verified: score", replay.Score, "on wave", replay.Wave)
+			return
+		}
+	}
+
+	data := &game.GameData{}
+
+	// Config: Enemies File (optional, only the built-in enemy types exist without it)
+	if Configs.EnemiesFile != "" {
+		data.Enemies, err = game.LoadEnemyDefinitions(source, Configs.EnemiesFile)
+		if err != nil {
+			fmt.Println("Invalid ENEMIES_FILE:", err)
+			os.Exit(1)
+		}
+	}
+
+	// Config: Pickups File (optional, only the built-in pickup types exist without it)
+	if Configs.PickupsFile != "" {
+		data.Pickups, err = game.LoadPickupDefinitions(source, Configs.PickupsFile)
+		if err != nil {
+			fmt.Println("Invalid PICKUPS_FILE:", err)
+			os.Exit(1)
+		}
 	}
 
-	// Config: Window Height
-	window_height, err := strconv.Atoi(Configs["WINDOW_HEIGHT"])
+	// Config: Waves File (optional, waves are procedural without it)
+	if Configs.WavesFile != "" {
+		data.Campaign, err = game.LoadCampaign(source, Configs.WavesFile)
+		if err != nil {
+			fmt.Println("Invalid WAVES_FILE:", err)
+			os.Exit(1)
+		}
+	}
+
+	err = data.Validate()
 	if err != nil {
-		panic(err)
+		fmt.Println("Invalid game data:", err)
+		os.Exit(1)
+	}
+
+	// Sprites: Check their hitboxes, before any collides
+	err = assets.ValidateHitboxes()
+	if err != nil {
+		fmt.Println("Invalid sprite hitboxes:", err)
+		os.Exit(1)
 	}
 
 	window_icon, _ := assets.GetWindowIconImages()
 
 	ebiten.SetRunnableOnUnfocused(false)
-	ebiten.SetWindowSize(window_width, window_height)
+	// Config: Window Width & Height
+	ebiten.SetWindowSize(Configs.WindowWidth, Configs.WindowHeight)
 	ebiten.SetWindowTitle(WINDOW_TITLE + " | " + VERSION)
 	ebiten.SetWindowIcon(window_icon)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	// Config: Fullscreen Enabled
-	if Configs["FULLSCREEN_ENABLED"] == "1" {
+	if Configs.FullscreenEnabled && replay == nil {
 		ebiten.SetFullscreen(true)
 	}
 
+	// Config: TPS (the simulation runs at a fixed rate, whatever the TPS)
+	if Configs.TPS == 0 {
+		ebiten.SetTPS(ebiten.SyncWithFPS)
+	} else {
+		ebiten.SetTPS(Configs.TPS)
+	}
+
 	// Initialize a new game
-	g := game.NewGame(Configs)
+	var g *game.Game
+	if replay != nil {
+		// Replays are played back in a window the size of the recorded arena
+		ebiten.SetWindowSize(int(replay.Width), int(replay.Height))
+		g = game.NewReplayGame(replay)
+	} else {
+		g = game.NewGame(Configs, data)
+
+		// Reload the configs when their file changes, while the game runs
+		g.WatchConfigs(source, configFlags)
+	}
 
 	// Run the game
 	err = ebiten.RunGame(g)
@@ -55,12 +150,3 @@ func main() {
 		panic(err)
 	}
 }
-
-func getRuntimeDirectory() string {
-	_, file, _, ok := runtime.Caller(1)
-	if ok {
-		return path.Dir(file)
-	}
-
-	return ""
-}
@@ -0,0 +1,7 @@
+// Package configs embeds the default configs file and game data, used when no configs file is found
+package configs
+
+import "embed"
+
+//go:embed *.env *.json
+var Files embed.FS
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"image"
 	_ "image/png"
+	"maps"
 	"math"
 	"path/filepath"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -20,21 +22,47 @@ var SpriteMap = map[string]SpriteInfo{
 	},
 	"player": {
 		filename: "player.png",
+		hitbox: &Hitbox{
+			Shape:  "polygon",
+			Points: [][2]float64{{0, -37}, {56, 12}, {45, 37}, {-45, 37}, {-56, 12}},
+		},
 	},
 	"enemy": {
 		filename: "enemy.png",
+		hitbox: &Hitbox{
+			Shape:  "polygon",
+			Points: [][2]float64{{-35, -42}, {35, -42}, {46, -15}, {0, 42}, {-46, -15}},
+		},
 	},
 	"boss": {
 		filename: "boss.png",
+		hitbox: &Hitbox{
+			Shape:  "circle",
+			Radius: 40,
+		},
 	},
 	"laser_blue": {
 		filename: "laser_blue.png",
+		hitbox: &Hitbox{
+			Shape:  "box",
+			Width:  9,
+			Height: 54,
+		},
 	},
 	"laser_red": {
 		filename: "laser_red.png",
+		hitbox: &Hitbox{
+			Shape:  "box",
+			Width:  9,
+			Height: 54,
+		},
 	},
 	"pill_blue": {
 		filename: "pill_blue.png",
+		hitbox: &Hitbox{
+			Shape:  "circle",
+			Radius: 11,
+		},
 	},
 	"bolt_bronze": {
 		filename: "bolt_bronze.png",
@@ -103,6 +131,96 @@ func (s *Sprite) Translate(op *ebiten.DrawImageOptions, scale float64, x float64
 	s.resetSpriteXY(op, scale)
 }
 
+// Gets the collision shape of the sprite, or a box the size of the image if it doesn't declare one
+func (s *Sprite) GetHitbox() *Hitbox {
+	if s.Info.hitbox == nil {
+		s.Info.hitbox = &Hitbox{
+			Shape:  "box",
+			Width:  float64(s.Image.Bounds().Dx()),
+			Height: float64(s.Image.Bounds().Dy()),
+		}
+	}
+
+	return s.Info.hitbox
+}
+
+// Checks the hitbox of every sprite in the sprite map, so invalid ones are caught when the game starts instead of when they collide
+func ValidateHitboxes() error {
+
+	for _, name := range slices.Sorted(maps.Keys(SpriteMap)) {
+		hitbox := SpriteMap[name].hitbox
+		if hitbox == nil {
+			continue
+		}
+
+		err := hitbox.Validate()
+		if err != nil {
+			return errors.New("invalid hitbox for sprite \"" + name + "\": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Checks the hitbox has a known shape with a size, and polygons are convex with at least 3 distinct points
+func (h *Hitbox) Validate() error {
+
+	switch h.Shape {
+	case "circle":
+		if h.Radius <= 0 {
+			return errors.New("circle radius must be greater than 0")
+		}
+
+	case "box":
+		if h.Width <= 0 || h.Height <= 0 {
+			return errors.New("box width and height must be greater than 0")
+		}
+
+	case "polygon":
+		if len(h.Points) < 3 {
+			return errors.New("polygons need at least 3 points")
+		}
+
+		// Every corner turns the same way (no collinear points), and the turns add up to a single loop (no self-intersections)
+		turning := 0.0
+		sign := 0.0
+
+		for i, point := range h.Points {
+			next := h.Points[(i+1)%len(h.Points)]
+			after := h.Points[(i+2)%len(h.Points)]
+
+			ax, ay := next[0]-point[0], next[1]-point[1]
+			bx, by := after[0]-next[0], after[1]-next[1]
+
+			cross := ax*by - ay*bx
+			if cross == 0 {
+				return errors.New("polygons cannot have repeated or collinear points")
+			}
+
+			if sign != 0 && math.Signbit(cross) != math.Signbit(sign) {
+				return errors.New("polygons must be convex")
+			}
+			sign = cross
+
+			turning += math.Atan2(cross, ax*bx+ay*by)
+		}
+
+		if math.Abs(math.Abs(turning)-2*math.Pi) > 1e-6 {
+			return errors.New("polygons must be convex")
+		}
+
+	default:
+		return errors.New("unknown shape \"" + h.Shape + "\"")
+	}
+
+	return nil
+}
+
+// Gets where the center of the image is drawn by Translate, which offsets non-square sprites vertically
+func (s *Sprite) GetCenter(scale float64, x float64, y float64) (float64, float64) {
+	return x, y + float64(s.Image.Bounds().Dy()-s.Image.Bounds().Dx())*scale/2
+}
+
 func LoadFont(path string, filename string) ([]byte, error) {
 	if filename == "" {
 		return nil, errors.New("filename cannot be empty")
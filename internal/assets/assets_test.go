@@ -0,0 +1,36 @@
+package assets
+
+import "testing"
+
+func TestValidateHitboxes(t *testing.T) {
+
+	err := ValidateHitboxes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string]*Hitbox{
+		"unknown shape":     {Shape: "triangle"},
+		"empty circle":      {Shape: "circle"},
+		"flat box":          {Shape: "box", Width: 10},
+		"two points":        {Shape: "polygon", Points: [][2]float64{{0, 0}, {10, 0}}},
+		"collinear points":  {Shape: "polygon", Points: [][2]float64{{0, 0}, {10, 0}, {20, 0}}},
+		"repeated point":    {Shape: "polygon", Points: [][2]float64{{0, 0}, {10, 0}, {10, 0}, {0, 10}}},
+		"concave":           {Shape: "polygon", Points: [][2]float64{{0, 0}, {10, 0}, {5, 2}, {10, 10}, {0, 10}}},
+		"self-intersecting": {Shape: "polygon", Points: [][2]float64{{0, -10}, {6, 8}, {-10, -3}, {10, -3}, {-6, 8}}},
+	}
+
+	for name, hitbox := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if hitbox.Validate() == nil {
+				t.Errorf("expected the hitbox to be invalid")
+			}
+		})
+	}
+
+	// Either winding order is convex
+	square := &Hitbox{Shape: "polygon", Points: [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}
+	if err := square.Validate(); err != nil {
+		t.Errorf("expected the square to be valid, got: %v", err)
+	}
+}
@@ -6,6 +6,18 @@ type SpriteInfo struct {
 	name     string // populated automatically
 	path     string // defaults to "./"
 	filename string
+	hitbox   *Hitbox // defaults to a box the size of the image
+}
+
+// Collision shape of a sprite, in pixels from the center of the image (before scaling and rotation)
+type Hitbox struct {
+	Shape   string       // "circle", "box" or "polygon"
+	Radius  float64      // circle
+	Width   float64      // box
+	Height  float64      // box
+	Points  [][2]float64 // polygon, convex, in clockwise order
+	OffsetX float64
+	OffsetY float64
 }
 
 type Sprite struct {
@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"io"
+	"io/fs"
 	"path/filepath"
 
 	ebitenAudio "github.com/hajimehoshi/ebiten/v2/audio"
@@ -22,7 +23,8 @@ var audioFiles embed.FS
 
 const sampleRate = 48000
 
-var context = ebitenAudio.NewContext(sampleRate)
+// Created with the first audio, so programs without audio (e.g. headless games & tests) don't need an audio device
+var context *ebitenAudio.Context
 
 func NewAudio(filename string, filetype string) (*Audio, error) {
 
@@ -57,6 +59,10 @@ func NewAudio(filename string, filetype string) (*Audio, error) {
 		return nil, errors.New("filetype " + filetype + " not supported")
 	}
 
+	if context == nil {
+		context = ebitenAudio.NewContext(sampleRate)
+	}
+
 	player, err = context.NewPlayerF32(d)
 	if err != nil {
 		return nil, err
@@ -85,6 +91,12 @@ func (a *Audio) Play() {
 	a.Player.Play()
 }
 
+// Plays from the start, at its volume scaled (e.g. by the volume of every SFX)
+func (a *Audio) PlayScaled(scale float64) {
+	a.Player.SetVolume(a.volume * scale)
+	a.Play()
+}
+
 func (a *Audio) Continue() {
 	if !a.Player.IsPlaying() {
 		a.Player.Play()
@@ -97,6 +109,12 @@ func (a *Audio) Pause() {
 	}
 }
 
+// Checks if there's an audio file with the filename, to catch missing files before they're decoded
+func Exists(filename string) bool {
+	_, err := fs.Stat(audioFiles, filename)
+	return filename != "" && err == nil
+}
+
 func loadAudioFile(path string, filename string) ([]byte, error) {
 
 	if filename == "" {
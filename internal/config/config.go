@@ -0,0 +1,384 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Environment variables override the configs file, e.g. SPACE_SHOOTER_MUSIC_VOLUME=0
+const ENV_PREFIX = "SPACE_SHOOTER_"
+
+// Gets the configs the game runs with when nothing overrides them
+func NewDefaultConfig() *Config {
+	return &Config{
+		// Window
+		WindowWidth:       1280,
+		WindowHeight:      720,
+		FullscreenEnabled: false,
+		TPS:               60,
+
+		// Game
+		GameSeed:           0,
+		TimeScale:          1.0,
+		EnemySpawnTime:     5,
+		PickupSpawnTime:    10,
+		PickupsFile:        "",
+		MaxEnemiesPerWave:  5,
+		WavesFile:          "",
+		DrawCollisionRects: false,
+		DrawDamageNumbers:  true,
+		SaveFileName:       "space-shooter.save",
+		RecordReplays:      true,
+
+		// Player
+		PlayerScale:            0.6,
+		PlayerHp:               100.0,
+		PlayerFireRate:         6.0,
+		PlayerProjectileSpeed:  20.0,
+		PlayerProjectileDamage: 5.0,
+
+		// Enemy
+		EnemyScale:            0.6,
+		EnemyHp:               15.0,
+		EnemyFireRate:         0.5,
+		EnemyProjectileSpeed:  5.0,
+		EnemyProjectileDamage: 10.0,
+		EnemyPointWorth:       10,
+		EnemiesFile:           "",
+
+		// Volume for Audio (Music & SFX)
+		MusicVolume:  0.5,
+		AttackVolume: 0.25,
+		SfxVolume:    1.0,
+
+		// Gamepad
+		GamepadDeadZone: 0.2,
+	}
+}
+
+// Loads the configs in layers, each one overriding the previous: defaults, the configs file, the settings, the environment and the flags.
+// The configs are validated once every layer is applied
+func Load(source *Source, flags map[string]string) (*Config, error) {
+
+	c := NewDefaultConfig()
+
+	err := c.LoadFile(source, source.name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.LoadEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range GetNames() {
+		value, ok := flags[name]
+		if !ok {
+			continue
+		}
+
+		err = c.Set(name, value)
+		if err != nil {
+			return nil, errors.New("flag -" + getFlagName(name) + ": " + err.Error())
+		}
+	}
+
+	err = c.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Creates the configs from a snapshot of values (e.g. the one stored in a replay), the missing ones keep their default
+func FromMap(values map[string]string) (*Config, error) {
+
+	c := NewDefaultConfig()
+
+	for _, name := range GetNames() {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		err := c.Set(name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Gets the names of every config, in the order they're declared
+func GetNames() []string {
+
+	t := reflect.TypeFor[Config]()
+	names := make([]string, 0, t.NumField())
+
+	for i := range t.NumField() {
+		names = append(names, t.Field(i).Tag.Get("config"))
+	}
+
+	return names
+}
+
+// Registers a flag for every config on the flag set, e.g. -music-volume for MUSIC_VOLUME.
+// The values are collected in the returned map once the flags are parsed, to be applied last by Load
+func RegisterFlags(fs *flag.FlagSet) map[string]string {
+
+	values := make(map[string]string)
+	t := reflect.TypeFor[Config]()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := field.Tag.Get("config")
+
+		fs.Func(getFlagName(name), field.Tag.Get("usage")+" (overrides "+name+")", func(value string) error {
+			values[name] = value
+			return nil
+		})
+	}
+
+	return values
+}
+
+// Applies a configs file on top of the current configs. Unknown configs are an error, as they're most likely a typo
+func (c *Config) LoadFile(files fs.FS, filePath string) error {
+
+	file, err := files.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	values, err := godotenv.Parse(file)
+	if err != nil {
+		return errors.New("cannot decode configs with filename \"" + filePath + "\": " + err.Error())
+	}
+
+	names := GetNames()
+
+	// Sorted, so the same file always reports the same error
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !slices.Contains(names, key) {
+			return errors.New(filepath.Base(filePath) + ": unknown config \"" + key + "\"")
+		}
+
+		err = c.Set(key, values[key])
+		if err != nil {
+			return errors.New(filepath.Base(filePath) + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Applies the environment variables (the config names prefixed by ENV_PREFIX) on top of the current configs
+func (c *Config) LoadEnv() error {
+
+	for _, name := range GetNames() {
+		value, ok := os.LookupEnv(ENV_PREFIX + name)
+		if !ok {
+			continue
+		}
+
+		err := c.Set(name, value)
+		if err != nil {
+			return errors.New("environment variable " + ENV_PREFIX + name + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Sets a config from its text value, as written in the configs file
+func (c *Config) Set(name string, value string) error {
+
+	field, ok := c.getField(name)
+	if !ok {
+		return errors.New("unknown config \"" + name + "\"")
+	}
+
+	value = strings.TrimSpace(value)
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New(name + ": \"" + value + "\" isn't a boolean (use 0 or 1)")
+		}
+		field.SetBool(val)
+
+	case reflect.Int, reflect.Int64:
+		val, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New(name + ": \"" + value + "\" isn't a whole number")
+		}
+		field.SetInt(val)
+
+	case reflect.Float64:
+		val, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New(name + ": \"" + value + "\" isn't a number")
+		}
+		field.SetFloat(val)
+
+	default:
+		return errors.New(name + ": unsupported config type " + field.Kind().String())
+	}
+
+	return nil
+}
+
+// Gets a config as text, the way it's written in the configs file
+func (c *Config) Get(name string) string {
+
+	field, ok := c.getField(name)
+	if !ok {
+		return ""
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		if field.Bool() {
+			return "1"
+		}
+		return "0"
+
+	case reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 64)
+
+	default:
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+// Gets every config as text, e.g. to store a snapshot of them in a replay
+func (c *Config) ToMap() map[string]string {
+
+	values := make(map[string]string)
+	for _, name := range GetNames() {
+		values[name] = c.Get(name)
+	}
+
+	return values
+}
+
+// Checks every config is within its range
+func (c *Config) Validate() error {
+
+	rules := []struct {
+		name  string
+		valid bool
+		rule  string
+	}{
+		{"WINDOW_WIDTH", c.WindowWidth > 0, "above 0"},
+		{"WINDOW_HEIGHT", c.WindowHeight > 0, "above 0"},
+		{"TPS", c.TPS >= 0, "0 or above"},
+		{"TIME_SCALE", c.TimeScale > 0, "above 0"},
+		{"ENEMY_SPAWN_TIME", c.EnemySpawnTime > 0, "above 0"},
+		{"PICKUP_SPAWN_TIME", c.PickupSpawnTime > 0, "above 0"},
+		{"MAX_ENEMIES_PER_WAVE", c.MaxEnemiesPerWave > 0, "above 0"},
+		{"SAVE_FILE_NAME", filepath.Ext(c.SaveFileName) == ".save" && filepath.Base(c.SaveFileName) == c.SaveFileName, "a file name ending in \".save\""},
+		{"PLAYER_SCALE", c.PlayerScale > 0, "above 0"},
+		{"PLAYER_HP", c.PlayerHp > 0, "above 0"},
+		{"PLAYER_FIRE_RATE", c.PlayerFireRate > 0, "above 0"},
+		{"PLAYER_PROJECTILE_SPEED", c.PlayerProjectileSpeed >= 0, "0 or above"},
+		{"PLAYER_PROJECTILE_DAMAGE", c.PlayerProjectileDamage > 0, "above 0"},
+		{"ENEMY_SCALE", c.EnemyScale > 0, "above 0"},
+		{"ENEMY_HP", c.EnemyHp > 0, "above 0"},
+		{"ENEMY_FIRE_RATE", c.EnemyFireRate > 0, "above 0"},
+		{"ENEMY_PROJECTILE_SPEED", c.EnemyProjectileSpeed >= 0, "0 or above"},
+		{"ENEMY_PROJECTILE_DAMAGE", c.EnemyProjectileDamage > 0, "above 0"},
+		{"ENEMY_POINT_WORTH", c.EnemyPointWorth >= 0, "0 or above"},
+		{"MUSIC_VOLUME", c.MusicVolume >= 0 && c.MusicVolume <= 1, "from 0 to 1"},
+		{"ATTACK_VOLUME", c.AttackVolume >= 0 && c.AttackVolume <= 1, "from 0 to 1"},
+		{"SFX_VOLUME", c.SfxVolume >= 0 && c.SfxVolume <= 1, "from 0 to 1"},
+		{"GAMEPAD_DEAD_ZONE", c.GamepadDeadZone >= 0 && c.GamepadDeadZone < 1, "from 0 to below 1"},
+	}
+
+	for _, rule := range rules {
+		if !rule.valid {
+			return errors.New(rule.name + " must be " + rule.rule + ", but it's \"" + c.Get(rule.name) + "\"")
+		}
+	}
+
+	return nil
+}
+
+// Prints every config in the configs file format, with its description
+func (c *Config) Print(w io.Writer) error {
+
+	t := reflect.TypeFor[Config]()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := field.Tag.Get("config")
+
+		// Quoted when empty, or the description would be read as the value
+		value := c.Get(name)
+		if value == "" {
+			value = "\"\""
+		}
+
+		_, err := fmt.Fprintln(w, name+": "+value+" # "+field.Tag.Get("usage"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gets the field of a config by its name
+func (c *Config) getField(name string) (reflect.Value, bool) {
+
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		if t.Field(i).Tag.Get("config") == name {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// Gets the flag of a config, e.g. -music-volume for MUSIC_VOLUME
+func getFlagName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
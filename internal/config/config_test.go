@@ -0,0 +1,161 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Writes a file, creating its folder
+func writeTestFile(t *testing.T, path string, content string) {
+
+	err := os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Each layer overrides the previous ones: defaults, the configs file, the settings, the environment and the flags
+func TestLoadLayers(t *testing.T) {
+
+	// The settings file is in the user's config directory
+	userConfigDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userConfigDir)
+	t.Setenv("HOME", userConfigDir)
+	t.Setenv("AppData", userConfigDir)
+
+	configsPath := filepath.Join(t.TempDir(), CONFIG_FILE_NAME)
+	writeTestFile(t, configsPath, "WINDOW_HEIGHT: 600\nWINDOW_WIDTH: 800\nATTACK_VOLUME: 0.1\nMUSIC_VOLUME: 0.1\n")
+
+	settingsPath, err := GetSettingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, settingsPath, "WINDOW_WIDTH=900\nATTACK_VOLUME=0.2\nMUSIC_VOLUME=0.2\n")
+
+	t.Setenv(ENV_PREFIX+"ATTACK_VOLUME", "0.3")
+	t.Setenv(ENV_PREFIX+"MUSIC_VOLUME", "0.3")
+
+	c, err := Load(NewFileSource(configsPath), map[string]string{"MUSIC_VOLUME": "0.4"})
+	if err != nil {
+		t.Fatalf("expected the configs to load, got: %v", err)
+	}
+
+	expected := map[string]string{
+		"TPS":           "60",  // default
+		"WINDOW_HEIGHT": "600", // configs file
+		"WINDOW_WIDTH":  "900", // settings
+		"ATTACK_VOLUME": "0.3", // environment
+		"MUSIC_VOLUME":  "0.4", // flag
+	}
+
+	for name, value := range expected {
+		if c.Get(name) != value {
+			t.Errorf("%s: expected %s, got %s", name, value, c.Get(name))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	err := NewDefaultConfig().Validate()
+	if err != nil {
+		t.Fatalf("expected the default configs to be valid, got: %v", err)
+	}
+
+	invalid := map[string]func(c *Config){
+		"window width of 0":         func(c *Config) { c.WindowWidth = 0 },
+		"negative TPS":              func(c *Config) { c.TPS = -1 },
+		"time scale of 0":           func(c *Config) { c.TimeScale = 0 },
+		"save file in a folder":     func(c *Config) { c.SaveFileName = "saves/space-shooter.save" },
+		"save file extension":       func(c *Config) { c.SaveFileName = "space-shooter.txt" },
+		"negative projectile speed": func(c *Config) { c.PlayerProjectileSpeed = -1 },
+		"music volume above 1":      func(c *Config) { c.MusicVolume = 1.5 },
+		"negative attack volume":    func(c *Config) { c.AttackVolume = -0.1 },
+		"SFX volume above 1":        func(c *Config) { c.SfxVolume = 2 },
+		"dead zone of 1":            func(c *Config) { c.GamepadDeadZone = 1 },
+	}
+
+	for name, change := range invalid {
+		t.Run(name, func(t *testing.T) {
+			c := NewDefaultConfig()
+			change(c)
+
+			if c.Validate() == nil {
+				t.Errorf("expected the configs to be invalid")
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+
+	c := NewDefaultConfig()
+
+	valid := map[string]string{
+		"WINDOW_WIDTH":       "1600",
+		"MUSIC_VOLUME":       " 0.75 ",
+		"FULLSCREEN_ENABLED": "true",
+		"SAVE_FILE_NAME":     "other.save",
+	}
+
+	for name, value := range valid {
+		err := c.Set(name, value)
+		if err != nil {
+			t.Errorf("%s: expected %q to be set, got: %v", name, value, err)
+		}
+	}
+
+	if c.WindowWidth != 1600 || c.MusicVolume != 0.75 || !c.FullscreenEnabled || c.SaveFileName != "other.save" {
+		t.Errorf("expected the values to be set, got %+v", c)
+	}
+
+	invalid := map[string][2]string{
+		"unknown config": {"UNKNOWN", "1"},
+		"bad int":        {"WINDOW_WIDTH", "wide"},
+		"float for int":  {"WINDOW_WIDTH", "1.5"},
+		"bad float":      {"MUSIC_VOLUME", "loud"},
+		"bad bool":       {"FULLSCREEN_ENABLED", "yes"},
+	}
+
+	for name, set := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if NewDefaultConfig().Set(set[0], set[1]) == nil {
+				t.Errorf("expected %s: %q to be an error", set[0], set[1])
+			}
+		})
+	}
+}
+
+func TestFromMap(t *testing.T) {
+
+	c, err := FromMap(map[string]string{"GAME_SEED": "42", "TIME_SCALE": "0.5"})
+	if err != nil {
+		t.Fatalf("expected the configs to load, got: %v", err)
+	}
+
+	// The missing configs keep their default
+	if c.GameSeed != 42 || c.TimeScale != 0.5 || c.WindowWidth != NewDefaultConfig().WindowWidth {
+		t.Errorf("expected the values on top of the defaults, got %+v", c)
+	}
+
+	invalid := map[string]map[string]string{
+		"bad int":      {"GAME_SEED": "random"},
+		"bad float":    {"TIME_SCALE": "fast"},
+		"bad bool":     {"RECORD_REPLAYS": "maybe"},
+		"out of range": {"TIME_SCALE": "0"},
+	}
+
+	for name, values := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := FromMap(values); err == nil {
+				t.Errorf("expected %v to be an error", values)
+			}
+		})
+	}
+}
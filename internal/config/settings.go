@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+)
+
+const SETTINGS_FILE_NAME = "settings.env"
+
+// Configs which can be changed in the game's settings, and are saved to the settings file
+var SETTINGS = []string{
+	"MUSIC_VOLUME",
+	"SFX_VOLUME",
+	"FULLSCREEN_ENABLED",
+	"WINDOW_WIDTH",
+	"WINDOW_HEIGHT",
+	"DRAW_COLLISION_RECTS",
+	"DRAW_DAMAGE_NUMBERS",
+}
+
+// Gets the path of the settings file, in the user's config directory (next to the save file)
+func GetSettingsPath() (string, error) {
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userConfigDir, USER_CONFIG_FOLDER, SETTINGS_FILE_NAME), nil
+}
+
+// Applies the settings file on top of the current configs, if there's one
+func (c *Config) LoadSettings() error {
+
+	path, err := GetSettingsPath()
+	if err != nil {
+		return nil
+	}
+
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return c.LoadFile(os.DirFS(filepath.Dir(path)), SETTINGS_FILE_NAME)
+}
+
+// Gets the current value of every setting, to find which ones are changed in the game's settings
+func (c *Config) GetSettings() map[string]string {
+
+	values := make(map[string]string)
+	for _, name := range SETTINGS {
+		values[name] = c.Get(name)
+	}
+
+	return values
+}
+
+// Saves the settings changed since the previous values to the settings file, so they're kept for the next time the game runs.
+// The others aren't saved, so they can still be changed in the configs file (and aren't fixed to an environment or flag override)
+func (c *Config) SaveSettings(previous map[string]string) error {
+
+	path, err := GetSettingsPath()
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]string)
+	for _, name := range SETTINGS {
+		value := c.Get(name)
+		if value != previous[name] {
+			changed[name] = value
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// Keep the settings saved before
+	values := make(map[string]string)
+
+	_, err = os.Stat(path)
+	if err == nil {
+		values, err = godotenv.Read(path)
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for name, value := range changed {
+		values[name] = value
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return err
+	}
+
+	return godotenv.Write(values, path)
+}
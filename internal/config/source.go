@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"go-game-space-shooter/configs"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const CONFIG_FILE_NAME = "configs.env"
+
+// Folder in the user's config directory, shared with the save file
+const USER_CONFIG_FOLDER = "go-game-space-shooter"
+
+// Finds the configs file: the given path (e.g. from a flag), the user's config directory, or the "configs" folder next to the executable.
+// When there's none, the embedded defaults are used, so a missing file doesn't prevent the game from launching
+func Find(path string) (*Source, error) {
+
+	// An explicit path must exist
+	if path != "" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.New("cannot find configs file \"" + path + "\"")
+		}
+		if info.IsDir() {
+			return nil, errors.New("configs file \"" + path + "\" is a folder")
+		}
+
+		return NewFileSource(path), nil
+	}
+
+	var candidates []string
+
+	userConfigDir, err := os.UserConfigDir()
+	if err == nil {
+		candidates = append(candidates, filepath.Join(userConfigDir, USER_CONFIG_FOLDER, CONFIG_FILE_NAME))
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		// Follow symlinks, so a linked binary finds the folder next to the real one
+		resolved, err := filepath.EvalSymlinks(executable)
+		if err == nil {
+			executable = resolved
+		}
+
+		candidates = append(candidates, filepath.Join(filepath.Dir(executable), "configs", CONFIG_FILE_NAME))
+	}
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() {
+			return NewFileSource(candidate), nil
+		}
+	}
+
+	return NewEmbeddedSource(), nil
+}
+
+// Creates a source from a configs file on disk
+func NewFileSource(path string) *Source {
+	return &Source{
+		files: os.DirFS(filepath.Dir(path)),
+		name:  filepath.Base(path),
+		path:  path,
+
+		embedded: false,
+	}
+}
+
+// Creates a source from the configs embedded in the executable
+func NewEmbeddedSource() *Source {
+	return &Source{
+		files: configs.Files,
+		name:  CONFIG_FILE_NAME,
+		path:  "embedded " + CONFIG_FILE_NAME,
+
+		embedded: true,
+	}
+}
+
+// Opens a file in the folder of the configs file. Game data files missing from it are read from the embedded ones,
+// so a configs file on its own (e.g. from -print-config) still finds them
+func (s *Source) Open(name string) (fs.File, error) {
+
+	file, err := s.files.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && !s.embedded {
+		return configs.Files.Open(name)
+	}
+
+	return file, err
+}
+
+// Gets where the configs file is, to show the user
+func (s *Source) GetPath() string {
+	return s.path
+}
+
+// Gets when the configs file was last modified, to watch it for changes (zero for the embedded configs, which can't change)
+func (s *Source) GetModTime() time.Time {
+
+	if s.embedded {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
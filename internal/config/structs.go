@@ -0,0 +1,58 @@
+package config
+
+import "io/fs"
+
+// Every config of the game, each field is named by its "config" tag in the configs file, the environment and the flags
+type Config struct {
+	// Window
+	WindowWidth       int  `config:"WINDOW_WIDTH" usage:"window width in pixels"`
+	WindowHeight      int  `config:"WINDOW_HEIGHT" usage:"window height in pixels"`
+	FullscreenEnabled bool `config:"FULLSCREEN_ENABLED" usage:"start in fullscreen"`
+	TPS               int  `config:"TPS" usage:"updates per second (0 syncs them with the display refresh rate)"`
+
+	// Game
+	GameSeed           int64   `config:"GAME_SEED" usage:"seed for the pseudo-randomness algorithm (0 generates a new one every time)"`
+	TimeScale          float64 `config:"TIME_SCALE" usage:"speed of the game (below 1 for slow-motion)"`
+	EnemySpawnTime     int     `config:"ENEMY_SPAWN_TIME" usage:"time for the next enemy wave to spawn (in seconds)"`
+	PickupSpawnTime    int     `config:"PICKUP_SPAWN_TIME" usage:"time for the next pickup to spawn (in seconds)"`
+	PickupsFile        string  `config:"PICKUPS_FILE" usage:"extra pickup types, relative to the configs folder"`
+	MaxEnemiesPerWave  int     `config:"MAX_ENEMIES_PER_WAVE" usage:"maximum number of enemies that spawn in each wave"`
+	WavesFile          string  `config:"WAVES_FILE" usage:"scripted waves, relative to the configs folder"`
+	DrawCollisionRects bool    `config:"DRAW_COLLISION_RECTS" usage:"draw the collision shapes of objects, for debugging purposes"`
+	DrawDamageNumbers  bool    `config:"DRAW_DAMAGE_NUMBERS" usage:"draw the damage dealt above the objects hit"`
+	SaveFileName       string  `config:"SAVE_FILE_NAME" usage:"save file name, stored in the user's config directory"`
+	RecordReplays      bool    `config:"RECORD_REPLAYS" usage:"record a replay of every run"`
+
+	// Player
+	PlayerScale            float64 `config:"PLAYER_SCALE" usage:"player scale"`
+	PlayerHp               float64 `config:"PLAYER_HP" usage:"player initial HP"`
+	PlayerFireRate         float64 `config:"PLAYER_FIRE_RATE" usage:"player shots per second"`
+	PlayerProjectileSpeed  float64 `config:"PLAYER_PROJECTILE_SPEED" usage:"player projectile speed"`
+	PlayerProjectileDamage float64 `config:"PLAYER_PROJECTILE_DAMAGE" usage:"player projectile damage"`
+
+	// Enemy
+	EnemyScale            float64 `config:"ENEMY_SCALE" usage:"enemy scale"`
+	EnemyHp               float64 `config:"ENEMY_HP" usage:"enemy initial HP"`
+	EnemyFireRate         float64 `config:"ENEMY_FIRE_RATE" usage:"enemy shots per second"`
+	EnemyProjectileSpeed  float64 `config:"ENEMY_PROJECTILE_SPEED" usage:"enemy projectile speed"`
+	EnemyProjectileDamage float64 `config:"ENEMY_PROJECTILE_DAMAGE" usage:"enemy projectile damage"`
+	EnemyPointWorth       int64   `config:"ENEMY_POINT_WORTH" usage:"how many points destroying an enemy awards"`
+	EnemiesFile           string  `config:"ENEMIES_FILE" usage:"extra enemy types, relative to the configs folder"`
+
+	// Volume for Audio (Music & SFX)
+	MusicVolume  float64 `config:"MUSIC_VOLUME" usage:"game music volume (from 0 to 1)"`
+	AttackVolume float64 `config:"ATTACK_VOLUME" usage:"player attack SFX volume (from 0 to 1)"`
+	SfxVolume    float64 `config:"SFX_VOLUME" usage:"volume of every SFX, on top of their own volume (from 0 to 1)"`
+
+	// Gamepad
+	GamepadDeadZone float64 `config:"GAMEPAD_DEAD_ZONE" usage:"how far the gamepad sticks are ignored from their center (from 0 to below 1)"`
+}
+
+// Where the configs file was found, the game data files (e.g. ENEMIES_FILE) are relative to it
+type Source struct {
+	files fs.FS  // the folder of the configs file
+	name  string // the configs file name, inside the folder
+	path  string // shown to the user
+
+	embedded bool
+}
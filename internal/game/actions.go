@@ -0,0 +1,317 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/joho/godotenv"
+)
+
+const (
+	ActionMoveUp Action = iota
+	ActionMoveDown
+	ActionMoveLeft
+	ActionMoveRight
+	ActionFire
+	ActionNextWeapon
+	ActionPreviousWeapon
+	ActionPause
+	ActionConfirm
+	ActionBack
+	ActionCount
+)
+
+// Most bindings each action can have
+const ACTION_MAP_MAX_BINDINGS = 2
+
+// Stored in the save folder
+const ACTION_MAP_FILE_NAME = "bindings.env"
+
+var actionInfos = [ActionCount]ActionInfo{
+	ActionMoveUp:         {name: "MOVE_UP", label: "Move Up", defaults: []string{"W", "ArrowUp"}},
+	ActionMoveDown:       {name: "MOVE_DOWN", label: "Move Down", defaults: []string{"S", "ArrowDown"}},
+	ActionMoveLeft:       {name: "MOVE_LEFT", label: "Move Left", defaults: []string{"A", "ArrowLeft"}},
+	ActionMoveRight:      {name: "MOVE_RIGHT", label: "Move Right", defaults: []string{"D", "ArrowRight"}},
+	ActionFire:           {name: "FIRE", label: "Shoot (hold to charge)", defaults: []string{"Space", "MouseLeft"}},
+	ActionNextWeapon:     {name: "NEXT_WEAPON", label: "Next Weapon", defaults: []string{"E"}},
+	ActionPreviousWeapon: {name: "PREVIOUS_WEAPON", label: "Previous Weapon", defaults: []string{"Q"}},
+	ActionPause:          {name: "PAUSE", label: "Pause/Unpause", defaults: []string{"Escape", "P"}},
+	ActionConfirm:        {name: "CONFIRM", label: "Start/Restart", defaults: []string{"Space", "Enter"}},
+	ActionBack:           {name: "BACK", label: "Back/Quit", defaults: []string{"Escape"}},
+}
+
+// Mouse buttons which can be bound, by name
+var mouseButtonNames = map[string]ebiten.MouseButton{
+	"MouseLeft":   ebiten.MouseButtonLeft,
+	"MouseRight":  ebiten.MouseButtonRight,
+	"MouseMiddle": ebiten.MouseButtonMiddle,
+}
+
+// Creates an action map with the default bindings
+func NewActionMap() *ActionMap {
+	m := &ActionMap{
+		gamepads: NewGamepads(),
+	}
+	m.ResetToDefaults()
+
+	return m
+}
+
+// Loads the bindings file on top of the default bindings, if there's one
+func LoadActionMap(path string) (*ActionMap, error) {
+
+	m := NewActionMap()
+
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range values {
+		action, ok := getActionByName(name)
+		if !ok {
+			return nil, errors.New(filepath.Base(path) + ": unknown action \"" + name + "\"")
+		}
+
+		bindings, err := parseBindings(value)
+		if err != nil {
+			return nil, errors.New(filepath.Base(path) + ": " + name + ": " + err.Error())
+		}
+
+		m.bindings[action] = bindings
+	}
+
+	return m, nil
+}
+
+// Saves the bindings of every action, so they're kept for the next time the game runs
+func (m *ActionMap) Save(path string) error {
+
+	err := os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	for action := range ActionCount {
+		values[actionInfos[action].name] = m.GetBindingNames(action, ",")
+	}
+
+	return godotenv.Write(values, path)
+}
+
+func (m *ActionMap) ResetToDefaults() {
+	for action := range ActionCount {
+		bindings, err := parseBindings(strings.Join(actionInfos[action].defaults, ","))
+		if err != nil {
+			HandleError(err)
+		}
+
+		m.bindings[action] = bindings
+	}
+}
+
+// Reads the gamepads, on every update
+func (m *ActionMap) Update() {
+	m.gamepads.Update()
+}
+
+// Checks if any binding of the action, or gamepad button, is held down
+func (m *ActionMap) IsPressed(action Action) bool {
+	return slices.ContainsFunc(m.bindings[action], Binding.isPressed) || m.gamepads.IsPressed(action)
+}
+
+// Checks if any binding of the action, or gamepad button, was pressed on this update
+func (m *ActionMap) IsJustPressed(action Action) bool {
+	return slices.ContainsFunc(m.bindings[action], Binding.isJustPressed) || m.gamepads.IsJustPressed(action)
+}
+
+// Gets the binding of an action in a slot, if there's one
+func (m *ActionMap) GetBinding(action Action, slot int) (Binding, bool) {
+	if slot >= len(m.bindings[action]) {
+		return Binding{}, false
+	}
+
+	return m.bindings[action][slot], true
+}
+
+// Gets the names of the bindings of an action, joined by the separator
+func (m *ActionMap) GetBindingNames(action Action, separator string) string {
+
+	names := make([]string, 0, len(m.bindings[action]))
+	for _, binding := range m.bindings[action] {
+		names = append(names, binding.String())
+	}
+
+	return strings.Join(names, separator)
+}
+
+// Binds an action in a slot (or in the next free one), replacing the binding which was there.
+// A binding can only be in one slot of the same action
+func (m *ActionMap) SetBinding(action Action, slot int, binding Binding) {
+
+	bindings := slices.DeleteFunc(m.bindings[action], func(b Binding) bool {
+		return b == binding
+	})
+
+	if slot < len(bindings) {
+		bindings[slot] = binding
+	} else if len(bindings) < ACTION_MAP_MAX_BINDINGS {
+		bindings = append(bindings, binding)
+	}
+
+	m.bindings[action] = bindings
+}
+
+// Removes the binding of an action in a slot
+func (m *ActionMap) ClearBinding(action Action, slot int) {
+	if slot < len(m.bindings[action]) {
+		m.bindings[action] = slices.Delete(m.bindings[action], slot, slot+1)
+	}
+}
+
+// Reads the player input for the current tick from the bound keys & mouse buttons, and the gamepads
+func (m *ActionMap) ReadInput() *Input {
+	cursor := GetCursorVector()
+
+	// Switching to the next or previous weapon works like the mouse wheel
+	scroll := readWeaponScroll()
+	if m.IsJustPressed(ActionNextWeapon) {
+		scroll = 1
+	} else if m.IsJustPressed(ActionPreviousWeapon) {
+		scroll = -1
+	}
+
+	input := NewInput(
+		m.IsPressed(ActionMoveUp),
+		m.IsPressed(ActionMoveDown),
+		m.IsPressed(ActionMoveLeft),
+		m.IsPressed(ActionMoveRight),
+		m.IsPressed(ActionFire),
+		readWeaponSlot(),
+		scroll,
+		cursor.x,
+		cursor.y,
+	)
+
+	// Right stick: Aims instead of the cursor
+	if aim := m.gamepads.GetAim(); aim != nil {
+		input.aim = &Vector{x: aim.x, y: aim.y}
+	}
+
+	return input
+}
+
+func GetActionLabel(action Action) string {
+	return actionInfos[action].label
+}
+
+// Gets the key or mouse button pressed on this update, to bind it to an action
+func ReadJustPressedBinding() (Binding, bool) {
+
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) > 0 {
+		return Binding{key: keys[0]}, true
+	}
+
+	for _, button := range mouseButtonNames {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			return Binding{mouse: button, isMouse: true}, true
+		}
+	}
+
+	return Binding{}, false
+}
+
+// Gets the binding from its name: a key (e.g. "W", "Space", "ArrowUp") or a mouse button (e.g. "MouseLeft")
+func ParseBinding(name string) (Binding, error) {
+
+	name = strings.TrimSpace(name)
+
+	if button, ok := mouseButtonNames[name]; ok {
+		return Binding{mouse: button, isMouse: true}, nil
+	}
+
+	var key ebiten.Key
+	err := key.UnmarshalText([]byte(name))
+	if err != nil {
+		return Binding{}, errors.New("unknown key \"" + name + "\"")
+	}
+
+	return Binding{key: key}, nil
+}
+
+func (b Binding) String() string {
+
+	if b.isMouse {
+		for name, button := range mouseButtonNames {
+			if button == b.mouse {
+				return name
+			}
+		}
+	}
+
+	return b.key.String()
+}
+
+func (b Binding) isPressed() bool {
+	if b.isMouse {
+		return ebiten.IsMouseButtonPressed(b.mouse)
+	}
+
+	return ebiten.IsKeyPressed(b.key)
+}
+
+func (b Binding) isJustPressed() bool {
+	if b.isMouse {
+		return inpututil.IsMouseButtonJustPressed(b.mouse)
+	}
+
+	return inpututil.IsKeyJustPressed(b.key)
+}
+
+// Gets the bindings from their names, separated by commas (empty for none)
+func parseBindings(value string) ([]Binding, error) {
+
+	var bindings []Binding
+
+	for _, name := range strings.Split(value, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		binding, err := ParseBinding(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bindings) == ACTION_MAP_MAX_BINDINGS {
+			return nil, errors.New("an action can't have more than " + strconv.Itoa(ACTION_MAP_MAX_BINDINGS) + " bindings")
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	return bindings, nil
+}
+
+func getActionByName(name string) (Action, bool) {
+	for action := range ActionCount {
+		if actionInfos[action].name == name {
+			return action, true
+		}
+	}
+
+	return 0, false
+}
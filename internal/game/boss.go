@@ -0,0 +1,256 @@
+package game
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var bossAttackPatterns = []string{"radial", "spiral", "sweep", "summon"}
+
+// Phases of the built-in boss, starting at full HP
+var defaultBossPhases = []BossPhase{
+	{
+		Threshold: 1.0,
+		Attacks: []BossAttack{
+			{Pattern: "radial", Telegraph: 1.0, Duration: 1.0, Interval: 0.5, Cooldown: 2.0, Count: 12, Damage: 0.5},
+			{Pattern: "spiral", Telegraph: 1.0, Duration: 3.0, Interval: 0.1, Cooldown: 2.0, Count: 1, Step: 20, Damage: 0.5},
+		},
+	},
+	{
+		Threshold: 0.6,
+		Attacks: []BossAttack{
+			{Pattern: "sweep", Telegraph: 1.0, Duration: 2.0, Interval: 0.08, Cooldown: 1.5, Step: 120, Speed: 1.5, Damage: 0.5},
+			{Pattern: "summon", Telegraph: 1.5, Cooldown: 2.0, Count: 3, Minion: "basic"},
+			{Pattern: "radial", Telegraph: 0.75, Duration: 1.5, Interval: 0.5, Cooldown: 1.5, Count: 16, Damage: 0.5},
+		},
+	},
+	{
+		Threshold: 0.3,
+		Attacks: []BossAttack{
+			{Pattern: "spiral", Telegraph: 0.75, Duration: 3.0, Interval: 0.08, Cooldown: 1.0, Count: 3, Step: 15, Speed: 1.25, Damage: 0.5},
+			{Pattern: "sweep", Telegraph: 0.75, Duration: 1.5, Interval: 0.06, Cooldown: 1.0, Step: 180, Speed: 1.5, Damage: 0.5},
+			{Pattern: "summon", Telegraph: 1.0, Cooldown: 1.0, Count: 4, Minion: "basic"},
+		},
+	},
+}
+
+func NewBossEncounter(phases []BossPhase) *BossEncounter {
+	encounter := &BossEncounter{
+		phases: phases,
+		phase:  0,
+		attack: 0,
+	}
+
+	encounter.startTelegraph()
+
+	return encounter
+}
+
+func (b *BossEncounter) Update(e *Enemy, g *Game) {
+
+	// Change phase once the HP drops below the next phase threshold, interrupting the current attack
+	for b.phase+1 < len(b.phases) && e.health.current <= e.health.max*b.phases[b.phase+1].Threshold {
+		b.phase++
+		b.attack = 0
+		b.startTelegraph()
+	}
+
+	attack := b.getAttack()
+
+	b.timer.Update()
+
+	switch b.state {
+	case BossStateCooldown:
+		if b.timer.IsReady() {
+			b.startTelegraph()
+		}
+
+	case BossStateTelegraph:
+		if b.timer.IsReady() {
+			b.state = BossStateAttacking
+			b.timer = NewTimer(secondsToDuration(attack.Duration))
+			b.intervalTimer = NewTimer(secondsToDuration(attack.Interval))
+			b.intervalTimer.TriggerNow()
+			b.emitter = NewEmitter(attack.getEmitterPattern())
+
+			// Attacks start aimed at the player
+			dx, dy, _ := DistanceBetweenTwoPoints(e.transform, g.player.transform)
+			b.angle = math.Atan2(dy, dx)
+		}
+
+	case BossStateAttacking:
+		b.intervalTimer.Update()
+		if b.intervalTimer.IsReady() {
+			b.intervalTimer.Reset()
+			b.fire(e, g, attack)
+		}
+
+		// Summons only happen once
+		if b.timer.IsReady() || attack.Pattern == "summon" {
+			b.state = BossStateCooldown
+			b.timer = NewTimer(secondsToDuration(attack.Cooldown))
+			b.attack = (b.attack + 1) % len(b.phases[b.phase].Attacks)
+		}
+	}
+}
+
+// Checks if the boss is busy with a pattern attack, and shouldn't fire its regular shots
+func (b *BossEncounter) IsBusy() bool {
+	return b.state != BossStateCooldown
+}
+
+func (b *BossEncounter) GetPhase() int {
+	return b.phase
+}
+
+// Draws the telegraph around the boss, where its sprite is drawn (alpha being how far along the next tick is)
+func (b *BossEncounter) Draw(screen *ebiten.Image, e *Enemy, alpha float64) {
+
+	if b.state != BossStateTelegraph {
+		return
+	}
+
+	// Telegraph: A pulsing ring around the boss, which closes in as the attack gets nearer
+	transform := e.getInterpolatedTransform(alpha)
+	progress := b.timer.GetProgress()
+	radius := float64(e.renderer.sprite.Image.Bounds().Dx())*transform.scale/2 + 60*(1-progress)
+	opacity := uint8(100 + 155*math.Abs(math.Sin(progress*math.Pi*4)))
+
+	vector.StrokeCircle(screen, float32(transform.x), float32(transform.y), float32(radius), 3.0, color.RGBA{255, 60, 0, opacity}, true)
+}
+
+func (b *BossEncounter) getAttack() *BossAttack {
+	return &b.phases[b.phase].Attacks[b.attack]
+}
+
+func (b *BossEncounter) startTelegraph() {
+	b.state = BossStateTelegraph
+	b.timer = NewTimer(secondsToDuration(b.getAttack().Telegraph))
+}
+
+func (b *BossEncounter) fire(e *Enemy, g *Game, attack *BossAttack) {
+
+	switch attack.Pattern {
+	case "radial", "spiral":
+		// A ring of projectiles, or rotating arms turning a step after every shot
+		b.emitter.FireAngle(g, newBossShot(e, attack), b.angle, nil)
+
+	case "sweep":
+		// A stream of projectiles, sweeping across an arc centered on the player
+		arc := attack.Step * math.Pi / 180.0
+		b.emitter.FireAngle(g, newBossShot(e, attack), b.angle-arc/2+arc*b.timer.GetProgress(), nil)
+
+	case "summon":
+		// Minions spawn in a circle around the boss
+		for i := range attack.Count {
+			angle := b.angle + 2*math.Pi*float64(i)/float64(attack.Count)
+			x := e.transform.x + math.Cos(angle)*100
+			y := e.transform.y + math.Sin(angle)*100
+
+			g.addEnemy(NewEnemy(g.enemyTypes.Get(attack.Minion), x, y, 0))
+		}
+	}
+}
+
+// Gets the emitter pattern of a pattern attack
+func (a *BossAttack) getEmitterPattern() *EmitterPattern {
+
+	switch a.Pattern {
+	case "radial":
+		return &EmitterPattern{Pattern: "ring", Count: a.Count}
+	case "spiral":
+		return &EmitterPattern{Pattern: "spiral", Count: a.Count, Step: a.Step}
+	}
+
+	return &EmitterPattern{Pattern: "single"}
+}
+
+func newBossShot(e *Enemy, attack *BossAttack) *ProjectileShot {
+
+	speed := attack.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	damage := attack.Damage
+	if damage == 0 {
+		damage = 1
+	}
+
+	// Fired from the center of the boss
+	return &ProjectileShot{
+		ownerTag:   "enemy",
+		owner:      e.Entity,
+		spriteName: e.attack.spriteName,
+		velocity:   e.attack.velocity * speed,
+		damage:     e.attack.damage * damage,
+		critical:   false,
+		hitAudio:   e.attack.hitAudio,
+	}
+}
+
+// Checks if a boss is alive or about to spawn, which holds back the next waves
+func (g *Game) isBossPresent() bool {
+
+	for _, enemy := range g.enemies {
+		if enemy.isBoss {
+			return true
+		}
+	}
+
+	for _, pending := range g.pendingSpawns {
+		if pending.group.Boss || g.enemyTypes.Get(pending.group.Enemy).isBoss {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateBossPhases(phases []BossPhase) error {
+
+	for i, phase := range phases {
+		prefix := "phase " + strconv.Itoa(i+1) + ": "
+
+		if phase.Threshold <= 0 || phase.Threshold > 1 {
+			return errors.New(prefix + "threshold must be between 0 and 1")
+		}
+		if i == 0 && phase.Threshold != 1 {
+			return errors.New(prefix + "the first phase must start at full HP (a threshold of 1)")
+		}
+		if i > 0 && phase.Threshold >= phases[i-1].Threshold {
+			return errors.New(prefix + "thresholds must be in descending order")
+		}
+		if len(phase.Attacks) == 0 {
+			return errors.New(prefix + "must have at least one attack")
+		}
+
+		for _, attack := range phase.Attacks {
+			if !slices.Contains(bossAttackPatterns, attack.Pattern) {
+				return errors.New(prefix + "unknown attack pattern \"" + attack.Pattern + "\"")
+			}
+			if attack.Telegraph < 0 || attack.Duration < 0 || attack.Interval < 0 || attack.Cooldown < 0 {
+				return errors.New(prefix + "attack timings cannot be negative")
+			}
+			if attack.Pattern != "sweep" && attack.Count < 1 {
+				return errors.New(prefix + attack.Pattern + " attacks must have a count of at least 1")
+			}
+			if attack.Pattern == "summon" && attack.Minion == "" {
+				return errors.New(prefix + "summon attacks must have a minion")
+			}
+		}
+	}
+
+	return nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
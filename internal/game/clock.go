@@ -0,0 +1,77 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Simulation ticks per second, independent of ebiten's TPS. Velocities are in pixels per simulation tick
+const SIMULATION_TPS = 60
+
+// Most simulation ticks run on a single update, so the game slows down instead of freezing when it can't keep up
+const CLOCK_MAX_TICKS_PER_UPDATE = 5
+
+func NewClock(timeScale float64) *Clock {
+	return &Clock{
+		accumulator: 0,
+		timeScale:   timeScale,
+		lastUpdate:  time.Now(),
+	}
+}
+
+// Adds the time since the last update (scaled by the time scale), and gets how many fixed simulation ticks to run
+func (c *Clock) Advance() int {
+
+	c.delta = c.getUpdateDuration()
+	c.accumulator += time.Duration(float64(c.delta) * c.timeScale)
+
+	ticks := int(c.accumulator / GetTickDuration())
+	c.accumulator -= time.Duration(ticks) * GetTickDuration()
+
+	// Drop the time it can't catch up on
+	if ticks > CLOCK_MAX_TICKS_PER_UPDATE {
+		ticks = CLOCK_MAX_TICKS_PER_UPDATE
+		c.accumulator = 0
+	}
+
+	return ticks
+}
+
+// Gets how far along the next simulation tick is (from 0 to 1), to interpolate what's drawn between the last two ticks
+func (c *Clock) GetAlpha() float64 {
+	return math.Min(1, float64(c.accumulator)/float64(GetTickDuration()))
+}
+
+// Gets the real time of the last update, in seconds (not scaled by the time scale)
+func (c *Clock) GetDelta() float64 {
+	return c.delta.Seconds()
+}
+
+// Slows down (below 1) or speeds up (above 1) the simulation
+func (c *Clock) SetTimeScale(scale float64) {
+	c.timeScale = math.Max(0, scale)
+}
+
+func (c *Clock) GetTimeScale() float64 {
+	return c.timeScale
+}
+
+func GetTickDuration() time.Duration {
+	return time.Second / SIMULATION_TPS
+}
+
+// Gets the time between ebiten updates: fixed by the TPS, or measured when it's synced with the display
+func (c *Clock) getUpdateDuration() time.Duration {
+
+	now := time.Now()
+	elapsed := now.Sub(c.lastUpdate)
+	c.lastUpdate = now
+
+	if ebiten.TPS() == ebiten.SyncWithFPS {
+		return elapsed
+	}
+
+	return time.Second / time.Duration(ebiten.TPS())
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+)
+
+// Checks the enemy types referenced by the game data all exist (bosses summoning regular enemies), and the pickup types are valid
+func (d *GameData) Validate() error {
+
+	names := slices.Clone(builtinEnemyTypes)
+
+	// The enemy types flagged as bosses, and their phases (both inherited from their base)
+	flagged := map[string]bool{"boss": true}
+	phases := map[string][]BossPhase{"boss": defaultBossPhases}
+
+	for _, definition := range d.Enemies {
+		if definition.Base != "" && !slices.Contains(names, definition.Base) {
+			return errors.New("enemy \"" + definition.Name + "\" has an unknown base \"" + definition.Base + "\" (bases must be defined before they're used)")
+		}
+
+		names = append(names, definition.Name)
+
+		base := definition.Base
+		if base == "" {
+			base = "basic"
+		}
+
+		flagged[definition.Name] = flagged[base]
+		if definition.Boss != nil {
+			flagged[definition.Name] = *definition.Boss
+		}
+		phases[definition.Name] = phases[base]
+		if definition.Phases != nil {
+			phases[definition.Name] = definition.Phases
+		}
+
+		for _, phase := range definition.Phases {
+			for _, attack := range phase.Attacks {
+				if attack.Pattern == "summon" && !slices.Contains(names, attack.Minion) {
+					return errors.New("enemy \"" + definition.Name + "\" summons an unknown minion \"" + attack.Minion + "\"")
+				}
+			}
+		}
+	}
+
+	// Bosses can't summon bosses (or themselves), which would nest encounters. Checked once every type is defined, as they can be replaced
+	for _, name := range names {
+		for _, phase := range phases[name] {
+			for _, attack := range phase.Attacks {
+				if attack.Pattern == "summon" && (flagged[attack.Minion] || phases[attack.Minion] != nil) {
+					return errors.New("enemy \"" + name + "\" summons a boss \"" + attack.Minion + "\" (minions cannot be bosses)")
+				}
+			}
+		}
+	}
+
+	for _, definition := range d.Pickups {
+		err := definition.validate()
+		if err != nil {
+			return errors.New("pickup \"" + definition.Name + "\": " + err.Error())
+		}
+	}
+
+	if d.Campaign != nil {
+		for i, wave := range d.Campaign.Waves {
+			for _, group := range wave.Groups {
+				if !slices.Contains(names, group.Enemy) {
+					return errors.New("wave " + strconv.Itoa(i+1) + ": unknown enemy type \"" + group.Enemy + "\"")
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package game
+
+import "testing"
+
+// Boss encounters start at full HP, and their minions aren't bosses
+func TestBossPhaseValidation(t *testing.T) {
+
+	summon := func(minion string) []BossPhase {
+		return []BossPhase{{Threshold: 1.0, Attacks: []BossAttack{{Pattern: "summon", Count: 1, Minion: minion}}}}
+	}
+
+	valid := &GameData{Enemies: []EnemyDefinition{{Name: "summoner", Base: "boss", Phases: summon("tank")}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected the data to be valid, got: %v", err)
+	}
+
+	boss := true
+	invalid := map[string]*GameData{
+		"summons the boss":   {Enemies: []EnemyDefinition{{Name: "summoner", Base: "boss", Phases: summon("boss")}}},
+		"summons itself":     {Enemies: []EnemyDefinition{{Name: "summoner", Phases: summon("summoner")}}},
+		"summons a boss too": {Enemies: []EnemyDefinition{{Name: "elite", Base: "boss"}, {Name: "summoner", Base: "boss", Phases: summon("elite")}}},
+		"minion made a boss": {Enemies: []EnemyDefinition{{Name: "basic", Boss: &boss}}},
+	}
+
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if data.Validate() == nil {
+				t.Errorf("expected the data to be invalid")
+			}
+		})
+	}
+
+	// The first phase starts at full HP
+	phases := summon("basic")
+	phases[0].Threshold = 0.8
+
+	if validateBossPhases(phases) == nil {
+		t.Errorf("expected a first phase below full HP to be invalid")
+	}
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+var emitterPatterns = []string{"single", "spread", "fan", "ring", "spiral", "burst"}
+
+// Seconds between the projectiles of a fan, when the pattern doesn't set a delay
+const EMITTER_FAN_DEFAULT_DELAY = 0.05
+
+func NewEmitter(pattern *EmitterPattern) *Emitter {
+	if pattern == nil {
+		pattern = &EmitterPattern{Pattern: "single"}
+	}
+
+	return &Emitter{
+		pattern:  pattern,
+		rotation: 0,
+	}
+}
+
+// Fires a volley aimed at the target, delayed projectiles are queued until their delay has passed
+func (em *Emitter) Fire(g *Game, shot *ProjectileShot, target *Vector) {
+
+	x := shot.owner.transform.x + shot.offsetX
+	y := shot.owner.transform.y + shot.offsetY
+
+	em.FireAngle(g, shot, math.Atan2(target.y-y, target.x-x), target)
+}
+
+// Fires a volley in a direction (in radians), the target is only used to re-aim bursts
+func (em *Emitter) FireAngle(g *Game, shot *ProjectileShot, aim float64, target *Vector) {
+
+	count := max(em.pattern.Count, 1)
+	step := em.pattern.Step * math.Pi / 180.0
+
+	delay := em.pattern.Delay
+	if em.pattern.Pattern == "fan" && delay == 0 {
+		delay = EMITTER_FAN_DEFAULT_DELAY
+	}
+
+	for i := range count {
+		angle := aim
+
+		switch em.pattern.Pattern {
+		case "spread", "fan":
+			// Projectiles a step apart, centered on the aim (fans sweep across one at a time)
+			angle = aim + step*(float64(i)-float64(count-1)/2)
+
+		case "ring":
+			// Projectiles evenly spaced around the shooter
+			angle = aim + 2*math.Pi*float64(i)/float64(count)
+
+		case "spiral":
+			// A ring, turning a step after every volley
+			angle = aim + em.rotation + 2*math.Pi*float64(i)/float64(count)
+		}
+
+		// Queued in place, so the queue (timers included) is reused between volleys without allocating
+		em.pending = append(em.pending, PendingShot{
+			shot:     *shot,
+			angle:    angle,
+			velocity: shot.velocity * em.getSpeedModifier(i, count),
+		})
+
+		n := len(em.pending) - 1
+		pending := &em.pending[n]
+		pending.timer.SetDuration(secondsToDuration(delay * float64(i)))
+
+		// Bursts are re-aimed at the target before every shot
+		if em.pattern.Pattern == "burst" {
+			pending.target = target
+		}
+
+		// Fired right away, and taken off the queue
+		if pending.timer.IsReady() {
+			em.emit(g, pending)
+
+			em.pending[n] = PendingShot{}
+			em.pending = em.pending[:n]
+		}
+	}
+
+	if em.pattern.Pattern == "spiral" {
+		em.rotation = math.Mod(em.rotation+step, 2*math.Pi)
+	}
+}
+
+// Fires the queued projectiles whose delay has passed
+func (em *Emitter) Update(g *Game) {
+
+	if len(em.pending) > 0 {
+		// Compacted in place, to reuse the queue
+		n := 0
+
+		for i := range em.pending {
+			pending := &em.pending[i]
+			pending.timer.Update()
+
+			if !pending.timer.IsReady() {
+				em.pending[n] = *pending
+				n++
+				continue
+			}
+
+			em.emit(g, pending)
+		}
+
+		clear(em.pending[n:])
+		em.pending = em.pending[:n]
+	}
+}
+
+// Drops the queued projectiles, e.g. when the shooter changes its pattern
+func (em *Emitter) Clear() {
+	clear(em.pending)
+	em.pending = em.pending[:0]
+}
+
+func (em *Emitter) emit(g *Game, pending *PendingShot) {
+
+	shot := &pending.shot
+
+	// Delayed projectiles follow the shooter
+	x := shot.owner.transform.x + shot.offsetX
+	y := shot.owner.transform.y + shot.offsetY
+
+	angle := pending.angle
+	if pending.target != nil {
+		angle = math.Atan2(pending.target.y-y, pending.target.x-x)
+	}
+
+	projectile := g.projectilePool.Get(shot.ownerTag, shot.spriteName, x, y, shot.owner.transform.angle, pending.velocity, shot.damage, shot.critical, shot.hitAudio)
+	projectile.SetProjectileDirection(&Vector{x: x + math.Cos(angle), y: y + math.Sin(angle)})
+	projectile.SetModifiers(shot.modifiers)
+	projectile.effect = shot.effect
+	projectile.renderer.tint = shot.tint
+
+	g.addProjectile(projectile)
+}
+
+// Gets the speed modifier of a projectile, going from the first to the last projectile of the volley
+func (em *Emitter) getSpeedModifier(i int, count int) float64 {
+
+	from := em.pattern.SpeedFrom
+	if from == 0 {
+		from = 1
+	}
+
+	to := em.pattern.SpeedTo
+	if to == 0 {
+		to = from
+	}
+
+	if count <= 1 {
+		return from
+	}
+
+	return from + (to-from)*float64(i)/float64(count-1)
+}
+
+func (p *EmitterPattern) validate() error {
+
+	if !slices.Contains(emitterPatterns, p.Pattern) {
+		return errors.New("unknown emitter pattern \"" + p.Pattern + "\"")
+	}
+	if p.Count < 0 {
+		return errors.New("emitter count cannot be negative")
+	}
+	if p.SpeedFrom < 0 || p.SpeedTo < 0 || p.Delay < 0 {
+		return errors.New("emitter speeds and delay cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/config"
+	"testing"
+)
+
+// Fires a fan until its queue is empty, then returns every projectile to the pool
+func fireAndRecycle(g *Game, em *Emitter, shot *ProjectileShot, target *Vector) {
+
+	em.Fire(g, shot, target)
+	for len(em.pending) > 0 {
+		em.Update(g)
+	}
+
+	for _, projectile := range g.projectiles {
+		projectile.disabled = true
+	}
+	g.removeDisabledEntities()
+}
+
+func newTestEmitter(g *Game) (*Emitter, *ProjectileShot, *Vector) {
+
+	em := NewEmitter(&EmitterPattern{Pattern: "fan", Count: 5, Step: 10})
+
+	shot := &ProjectileShot{
+		ownerTag:   "player",
+		owner:      g.player.Entity,
+		spriteName: g.player.attack.spriteName,
+		velocity:   g.player.attack.velocity,
+		damage:     g.player.attack.damage,
+	}
+
+	target := &Vector{x: g.player.transform.x, y: 0}
+
+	return em, shot, target
+}
+
+// Once the pool & queues have grown, firing and recycling projectiles doesn't allocate
+func TestEmitterFireAllocations(t *testing.T) {
+
+	g := NewHeadlessGame(config.NewDefaultConfig(), nil, 1280, 720)
+	em, shot, target := newTestEmitter(g)
+
+	// Grow the pool & queues
+	fireAndRecycle(g, em, shot, target)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		fireAndRecycle(g, em, shot, target)
+	})
+
+	if allocs != 0 {
+		t.Errorf("firing and recycling a volley allocates %v times, expected 0", allocs)
+	}
+}
+
+func BenchmarkEmitterFire(b *testing.B) {
+
+	g := NewHeadlessGame(config.NewDefaultConfig(), nil, 1280, 720)
+	em, shot, target := newTestEmitter(g)
+
+	fireAndRecycle(g, em, shot, target)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		fireAndRecycle(g, em, shot, target)
+	}
+}
@@ -1,131 +1,125 @@
 package game
 
 import (
-	"go-game-space-shooter/internal/assets"
-	"go-game-space-shooter/internal/audio"
 	"image/color"
 	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-func NewEnemy(enemyType string, spriteName string, x float64, y float64, angle float64) *Enemy {
-	sprite, err := assets.NewSprite(spriteName)
-	if err != nil {
-		HandleError(err)
-	}
-
-	hitAudio, err := audio.NewAudio("damage1.mp3", "mp3")
-	if err != nil {
-		HandleError(err)
-	}
-
+// Creates an enemy, its hit audio is shared by every enemy and set once it's added to the game
+func NewEnemy(archetype *EnemyArchetype, x float64, y float64, angle float64) *Enemy {
 	enemy := Enemy{
-		character: &Character{
-			position: &CharacterVector{
-				vector: &Vector{
-					x: float64(x),
-					y: float64(y),
-				},
+		Entity: &Entity{
+			transform: &Vector{
+				x:     float64(x),
+				y:     float64(y),
 				angle: angle,
-				scale: 0.6,
+				scale: archetype.scale,
 			},
+			renderer: NewSpriteRenderer(archetype.spriteName, SPRITE_LAYER_ENEMIES),
+			collider: NewColliderComponent(color.RGBA{255, 0, 0, 255}),
 			movement: &Movement{
-				velocity: 1.0,
+				velocity: archetype.velocity,
 			},
-			sprite: sprite,
-			hp: &Health{
-				max:     10.0,
-				current: 10.0,
+			health: &Health{
+				max:     archetype.hp,
+				current: archetype.hp,
+			},
+			attack: &Attack{
+				spriteName:       archetype.projectileSpriteName,
+				fireRate:         archetype.fireRate,
+				velocity:         archetype.projectileVelocity,
+				damage:           archetype.damage,
+				criticalChance:   0.0,
+				criticalModifier: 0.0,
+				modifiers:        archetype.modifiers,
+				effect:           archetype.effect,
 			},
 		},
-		attack: &Attack{
-			spriteName:       "laser_red",
-			fireRate:         0.5,
-			velocity:         5.0,
-			damage:           10.0,
-			criticalChance:   0.0,
-			criticalModifier: 0.0,
-			hitAudio:         hitAudio,
-		},
-		enemyType:           enemyType,
-		worthPoints:         10,
-		minLengthFromPlayer: 200.0,
+		archetype:           archetype,
+		behaviour:           NewEnemyBehaviour(archetype.behaviour),
+		enemyType:           archetype.name,
+		isBoss:              archetype.isBoss,
+		worthPoints:         archetype.worthPoints,
+		minLengthFromPlayer: archetype.minLengthFromPlayer,
 		isRunningAway:       false,
 		isStopped:           false,
-		disabled:            false,
 	}
 
-	// Apply configs
-	enemy.applyConfigs()
-
-	// Enemy type
-	switch enemy.enemyType {
-	case "tank":
-		enemy.character.position.scale = 1.0
-		enemy.character.hp.max *= 3.0
-		enemy.character.hp.current = enemy.character.hp.max
-	case "boss":
-		enemy.character.position.scale = 1.0
-		enemy.character.hp.max *= 20.0
-		enemy.character.hp.current = enemy.character.hp.max
-		enemy.attack.fireRate *= 6.0
-		enemy.attack.damage *= 3.0
-	default:
-		// Pass
-	}
+	// The enemy is its own AI
+	enemy.ai = &enemy
 
 	// Create attack timer
 	enemy.attack.timer = NewTimer(time.Millisecond * time.Duration(1.0/enemy.attack.fireRate*1000))
 
+	// Create attack emitter
+	enemy.attack.emitter = NewEmitter(archetype.emitter)
+
+	// Boss encounter, for enemy types with attack phases
+	if len(archetype.phases) > 0 {
+		enemy.encounter = NewBossEncounter(archetype.phases)
+	}
+
 	return &enemy
 }
 
-func (e *Enemy) Update(g *Game, p *Player) {
+// Applies a rebuilt enemy type (e.g. once the configs are reloaded), keeping the HP it has left
+func (e *Enemy) setArchetype(archetype *EnemyArchetype) {
 
-	if e.disabled {
-		return
-	}
+	e.transform.scale = archetype.scale
 
-	e.updateMovement(p)
-	e.updateAttack(g)
+	e.health.current *= archetype.hp / e.health.max
+	e.health.max = archetype.hp
+
+	e.attack.SetFireRate(archetype.fireRate)
+	e.attack.velocity = archetype.projectileVelocity
+	e.attack.damage = archetype.damage
+
+	e.worthPoints = archetype.worthPoints
+	e.archetype = archetype
 }
 
-func (e *Enemy) Draw(screen *ebiten.Image) {
+func (e *Enemy) Update(g *Game) {
 
 	if e.disabled {
 		return
 	}
 
-	op := &ebiten.DrawImageOptions{}
+	p := g.player
 
-	e.character.sprite.Rotate(op, e.character.position.angle)
-	e.character.sprite.Scale(op, e.character.position.scale)
-	e.character.sprite.Translate(op, e.character.position.scale, e.character.position.vector.x, e.character.position.vector.y)
+	e.updateMovement(g, p)
+	e.updateAttack(g)
 
-	screen.DrawImage(e.character.sprite.Image, op)
+	// Boss: Pattern attacks, only while the player is alive
+	if e.encounter != nil && !p.disabled {
+		e.encounter.Update(e, g)
+	}
+}
 
-	op.GeoM.Reset()
+// Draws the attack telegraphs around the enemy (its sprite is drawn by the world)
+func (e *Enemy) Draw(screen *ebiten.Image, alpha float64) {
 
-	// Config: Draw Colission Rects
-	if Configs["DRAW_COLLISION_RECTS"] == "1" && e.character.position.collision != nil {
-		// Draw collision rectangle
-		vector.StrokeRect(screen, float32(e.character.position.collision.x0), float32(e.character.position.collision.y0), float32(e.character.position.collision.x1-e.character.position.collision.x0), float32(e.character.position.collision.y1-e.character.position.collision.y0), 1.0, color.RGBA{255, 0, 0, 255}, true)
+	if e.disabled {
+		return
+	}
+
+	// Boss: Telegraph the next attack
+	if e.encounter != nil {
+		e.encounter.Draw(screen, e, alpha)
 	}
 }
 
-func SpawnEnemies(random *rand.Rand, enemies []*Enemy, currentWave int, max int) []*Enemy {
+func SpawnEnemies(random *rand.Rand, arena *Arena, enemyTypes *EnemyRegistry, enemies []*Enemy, currentWave int, max int) []*Enemy {
 
 	const OFFSET_Y int = 200
 
 	// Enemy Spawner: Boss!
 	if currentWave > 0 && currentWave%10 == 0 {
-		eX, eY := GetRandomSpawnPosition(random, OFFSET_Y)
-		enemies = append(enemies, NewEnemy("boss", "boss", eX, eY, 0))
+		eX, eY := GetRandomSpawnPosition(random, arena, OFFSET_Y)
+		enemies = append(enemies, NewEnemy(enemyTypes.Get("boss"), eX, eY, 0))
 
 		// Don't spawn other enemies in boss encounter
 		return enemies
@@ -133,172 +127,51 @@ func SpawnEnemies(random *rand.Rand, enemies []*Enemy, currentWave int, max int)
 
 	// Enemy Spawner: Basic
 	for range random.Intn(max) + 1 {
-		eX, eY := GetRandomSpawnPosition(random, OFFSET_Y)
-		enemies = append(enemies, NewEnemy("basic", "enemy", eX, eY, 0))
+		eX, eY := GetRandomSpawnPosition(random, arena, OFFSET_Y)
+		enemies = append(enemies, NewEnemy(enemyTypes.Get("basic"), eX, eY, 0))
 	}
 
 	// Enemy Spawner: Tank (50% chance after wave 5)
 	if currentWave >= 5 && random.Float64()*100.0 <= 50 {
-		eX, eY := GetRandomSpawnPosition(random, OFFSET_Y)
-		enemies = append(enemies, NewEnemy("tank", "enemy", eX, eY, 0))
+		eX, eY := GetRandomSpawnPosition(random, arena, OFFSET_Y)
+		enemies = append(enemies, NewEnemy(enemyTypes.Get("tank"), eX, eY, 0))
 	}
 
 	return enemies
 }
 
-func (e *Enemy) OffsetHp(offset float64) {
-
-	tmp := e.character.hp.current
-
-	e.character.hp.current += offset
-
-	if e.character.hp.current <= 0 {
-		e.character.hp.current = 0
-		e.disabled = true
-
-	} else if e.character.hp.current > e.character.hp.max {
-		e.character.hp.current = e.character.hp.max
-	}
-
-	if tmp > e.character.hp.current {
-		// ? DEBUG
-		// fmt.Println("Enemy hit:", tmp, "->", e.character.hp.current)
-	}
-}
-
-func (e *Enemy) applyConfigs() {
-
-	configs := e.getConfigs()
-
-	// Apply configs: Enemy Scale
-	if configs["enemy_scale"] > 0.00 {
-		e.character.position.scale = configs["enemy_scale"]
-	}
-
-	// Apply configs: Enemy HP
-	if configs["enemy_hp"] > 0.00 {
-		e.character.hp.max = configs["enemy_hp"]
-		e.character.hp.current = configs["enemy_hp"]
-	}
-
-	// Apply config: Enemy Fire Rate
-	if configs["enemy_fire_rate"] > 0.00 {
-		e.attack.fireRate = configs["enemy_fire_rate"]
-	}
-
-	// Apply config: Enemy Projectile Speed
-	if configs["enemy_projectile_speed"] >= 0.00 {
-		e.attack.velocity = configs["enemy_projectile_speed"]
-	}
-
-	// Apply config: Enemy Projectile Speed
-	if configs["enemy_projectile_damage"] > 0.00 {
-		e.attack.damage = configs["enemy_projectile_damage"]
-	}
-
-	// Apply config: Enemy Point Worth
-	if configs["enemy_point_worth"] > 0.00 {
-		e.worthPoints = int64(configs["enemy_point_worth"])
-	}
-}
-
-func (e *Enemy) getConfigs() map[string]float64 {
-	var val float64
-	var err error
+func (e *Enemy) updateMovement(g *Game, p *Player) {
 
-	configs := make(map[string]float64)
-
-	// Config: Enemy Scale
-	val, err = strconv.ParseFloat(Configs["ENEMY_SCALE"], 64)
-	if err == nil {
-		configs["enemy_scale"] = val
-	}
-
-	// Config: Enemy HP
-	val, err = strconv.ParseFloat(Configs["ENEMY_HP"], 64)
-	if err == nil {
-		configs["enemy_hp"] = val
-	}
-
-	// Config: Enemy Fire Rate
-	val, err = strconv.ParseFloat(Configs["ENEMY_FIRE_RATE"], 64)
-	if err == nil {
-		configs["enemy_fire_rate"] = val
-	}
-
-	// Config: Enemy Projectile Speed
-	val, err = strconv.ParseFloat(Configs["ENEMY_PROJECTILE_SPEED"], 64)
-	if err == nil {
-		configs["enemy_projectile_speed"] = val
-	}
-
-	// Config: Enemy Projectile Speed
-	val, err = strconv.ParseFloat(Configs["ENEMY_PROJECTILE_DAMAGE"], 64)
-	if err == nil {
-		configs["enemy_projectile_damage"] = val
-	}
-
-	// Config: Enemy Point Worth
-	val, err = strconv.ParseFloat(Configs["ENEMY_POINT_WORTH"], 64)
-	if err == nil {
-		configs["enemy_point_worth"] = val
-	}
-
-	return configs
-}
-
-func (e *Enemy) updateMovement(p *Player) {
-
-	dx, dy, length := DistanceBetweenTwoPoints(e.character.position.vector, p.character.position.vector)
-
-	velocityModifier := 0.0
+	var dx, dy float64
 
 	// Player is dead, enemy will go back to home base
 	if p.disabled {
+		dx, dy, _ = DistanceBetweenTwoPoints(e.transform, p.transform)
 		dx *= -1
 		dy *= -1
 		e.isRunningAway = true
 		e.isStopped = false
 
-	} else {
-
-		// If the enemy is within the minLengthFromPlayer, move back by inverting it's trajectory
-		if length < e.minLengthFromPlayer {
-			dx *= -1
-			dy *= -1
-			e.isRunningAway = true
-			e.isStopped = false
-
-			// If it's between minLengthFromPlayer and minLengthFromPlayer+100, it stops and still shoots
-		} else if (e.isRunningAway || e.isStopped) && length < e.minLengthFromPlayer+100 {
-			e.isRunningAway = false
-			e.isStopped = true
-
-			// Gets closer to the player
-		} else {
-			e.isRunningAway = false
-			e.isStopped = false
-		}
-	}
+		// Running away increases it's velocity
+		e.move(dx, dy, e.movement.velocity+1.0)
 
-	// if the enemy is running away, increase it's velocity
-	if e.isRunningAway {
-		velocityModifier = 1.0
-	}
-
-	if !e.isStopped {
-		e.character.position.vector.x += dx * (e.character.movement.velocity + velocityModifier)
-		e.character.position.vector.y += dy * (e.character.movement.velocity + velocityModifier)
+	} else {
+		dx, dy = e.behaviour.Move(e, g)
 	}
 
-	e.character.position.angle = ((math.Atan2(dy, dx) * 180) / math.Pi) - 90
+	e.transform.angle = ((math.Atan2(dy, dx) * 180) / math.Pi) - 90
 
-	// Update collision rectangle
-	x0, y0, x1, y1 := GetSpriteRectCoords(e.character.position.vector, e.character.sprite, e.character.position.scale)
-	e.character.position.collision = &CollisionRect{x0: x0 - 10, y0: y0 - 10, x1: x1 + 15, y1: y1 + 10}
+	// Update collision shape
+	g.world.UpdateCollider(e.Entity)
 
 	// ? DEBUG
-	// fmt.Println(e.character.position.collision.x0, e.character.position.collision.y0, e.character.position.collision.x1, e.character.position.collision.y1, float64(float64(e.character.sprite.Image.Bounds().Dx())*e.character.position.scale))
+	// fmt.Println(e.collider.GetBounds().x0, e.collider.GetBounds().y0, e.collider.GetBounds().x1, e.collider.GetBounds().y1, float64(float64(e.renderer.sprite.Image.Bounds().Dx())*e.transform.scale))
+}
+
+// Moves the enemy along a direction vector
+func (e *Enemy) move(dx float64, dy float64, velocity float64) {
+	e.transform.x += dx * velocity
+	e.transform.y += dy * velocity
 }
 
 func (e *Enemy) updateAttack(g *Game) {
@@ -307,25 +180,29 @@ func (e *Enemy) updateAttack(g *Game) {
 		return
 	}
 
+	e.attack.emitter.Update(g)
 	e.attack.timer.Update()
 
-	// If the enemy is running away, reset the timer so it doesn't shoot, and to wait for the next timer target
-	if e.isRunningAway {
+	// If the enemy is running away (or can't shoot right now), reset the timer so it doesn't shoot, and to wait for the next timer target
+	if e.isRunningAway || !e.behaviour.CanAttack(e) || (e.encounter != nil && e.encounter.IsBusy()) {
 		e.attack.timer.Reset()
 	}
 
 	if e.attack.timer.IsReady() {
 		e.attack.timer.Reset()
 
-		// Find middle of character position vector
-		projectileX := e.character.position.vector.x
-		projectileY := e.character.position.vector.y - ((float64(e.character.sprite.Image.Bounds().Dy())) / 2.0)
-
-		// Create a new projectile
-		projectile := NewProjectile("enemy", e.character, e.attack.spriteName, projectileX, projectileY, e.character.position.angle, e.attack.velocity, e.attack.damage, false, e.attack.hitAudio)
-		projectile.SetProjectileDirection(g.player.character.position.vector)
-
-		// Add to projectile list
-		g.projectiles = append(g.projectiles, projectile)
+		// Fire the projectiles, from the middle of character position vector
+		e.attack.emitter.Fire(g, &ProjectileShot{
+			ownerTag:   "enemy",
+			owner:      e.Entity,
+			spriteName: e.attack.spriteName,
+			offsetY:    -(float64(e.renderer.sprite.Image.Bounds().Dy())) / 2.0,
+			velocity:   e.attack.velocity,
+			damage:     e.attack.damage,
+			critical:   false,
+			modifiers:  e.attack.modifiers,
+			effect:     e.attack.effect,
+			hitAudio:   e.attack.hitAudio,
+		}, g.player.transform)
 	}
 }
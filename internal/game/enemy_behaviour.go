@@ -0,0 +1,317 @@
+package game
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	KAMIKAZE_ACCELERATION             = 0.05
+	KAMIKAZE_MAX_VELOCITY_MODIFIER    = 4.0
+	SNIPER_DISTANCE_MODIFIER          = 2.5
+	SWARM_NEIGHBOUR_RADIUS            = 150.0
+	SWARM_SEPARATION_RADIUS           = 50.0
+	SWARM_SEPARATION_WEIGHT           = 1.5
+	SWARM_ALIGNMENT_WEIGHT            = 0.5
+	SWARM_COHESION_WEIGHT             = 0.5
+	SWARM_STEERING                    = 0.1
+	SWARM_MAX_VELOCITY_MODIFIER       = 1.5
+	ORBIT_FLANK_DISTANCE_FROM_MINIMUM = 50.0
+)
+
+// Enemy behaviours by name, each enemy gets its own instance as some behaviours keep state
+var enemyBehaviours = map[string]func() EnemyBehaviour{
+	"approach": func() EnemyBehaviour { return &approachBehaviour{} },
+	"orbit":    func() EnemyBehaviour { return &orbitBehaviour{} },
+	"kamikaze": func() EnemyBehaviour { return &kamikazeBehaviour{} },
+	"sniper":   func() EnemyBehaviour { return &sniperBehaviour{} },
+	"flank":    func() EnemyBehaviour { return &flankBehaviour{} },
+	"swarm":    func() EnemyBehaviour { return &swarmBehaviour{} },
+}
+
+func NewEnemyBehaviour(name string) EnemyBehaviour {
+	if name == "" {
+		name = "approach"
+	}
+
+	newBehaviour, ok := enemyBehaviours[name]
+	if !ok {
+		HandleError(errors.New("enemy behaviour \"" + name + "\" does not exist"))
+	}
+
+	return newBehaviour()
+}
+
+// Approach: Gets closer to the player, backs off when too close, and stops in between to shoot
+type approachBehaviour struct{}
+
+func (b *approachBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+	return keepDistanceFromPlayer(e, g, e.minLengthFromPlayer)
+}
+
+func (b *approachBehaviour) CanAttack(e *Enemy) bool {
+	return true
+}
+
+// Sniper: Keeps a long distance from the player, and only shoots while stopped
+type sniperBehaviour struct{}
+
+func (b *sniperBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+	return keepDistanceFromPlayer(e, g, e.minLengthFromPlayer*SNIPER_DISTANCE_MODIFIER)
+}
+
+func (b *sniperBehaviour) CanAttack(e *Enemy) bool {
+	return e.isStopped
+}
+
+// Orbit: Strafes in circles around the player, at a fixed distance
+type orbitBehaviour struct {
+	direction float64
+}
+
+func (b *orbitBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+
+	// Pick the orbit direction once (clockwise or counter-clockwise)
+	if b.direction == 0 {
+		b.direction = 1
+		if g.random.Intn(2) == 0 {
+			b.direction = -1
+		}
+	}
+
+	e.isRunningAway = false
+	e.isStopped = false
+
+	dx, dy, length := DistanceBetweenTwoPoints(e.transform, g.player.transform)
+	radius := e.minLengthFromPlayer + ORBIT_FLANK_DISTANCE_FROM_MINIMUM
+
+	// Too far away, gets closer to the player first
+	if length > radius+100 {
+		e.move(dx, dy, e.movement.velocity)
+		return dx, dy
+	}
+
+	// Move along the tangent, correcting the distance towards the orbit radius
+	correction := math.Max(-1, math.Min(1, (length-radius)/100))
+	mx := -dy*b.direction + dx*correction
+	my := dx*b.direction + dy*correction
+
+	mx, my, _ = DistanceBetweenTwoPoints(&Vector{}, &Vector{x: mx, y: my})
+	e.move(mx, my, e.movement.velocity+0.5)
+
+	// Always faces the player
+	return dx, dy
+}
+
+func (b *orbitBehaviour) CanAttack(e *Enemy) bool {
+	return true
+}
+
+// Kamikaze: Dives into the player, accelerating, and explodes on contact
+type kamikazeBehaviour struct {
+	velocity float64
+}
+
+func (b *kamikazeBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+
+	if b.velocity == 0 {
+		b.velocity = e.movement.velocity
+	}
+
+	b.velocity = math.Min(b.velocity+KAMIKAZE_ACCELERATION, e.movement.velocity*KAMIKAZE_MAX_VELOCITY_MODIFIER)
+
+	e.isRunningAway = false
+	e.isStopped = false
+
+	dx, dy, _ := DistanceBetweenTwoPoints(e.transform, g.player.transform)
+	e.move(dx, dy, b.velocity)
+
+	// Explode on contact with the player
+	if e.collider.shape.Overlaps(g.player.collider.shape) {
+
+		// Play the hit audio
+		g.PlayAudio(e.attack.hitAudio)
+
+		// Hurt the player
+		g.player.TakeDamage(g, e.attack.damage, e.attack.effect)
+
+		// Destroyed, without awarding points
+		e.disabled = true
+	}
+
+	return dx, dy
+}
+
+func (b *kamikazeBehaviour) CanAttack(e *Enemy) bool {
+	return false
+}
+
+// Flank: Moves to the side of the player, slightly behind where it's aiming
+type flankBehaviour struct {
+	side float64
+}
+
+func (b *flankBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+
+	// Pick the side once (left or right of the player)
+	if b.side == 0 {
+		b.side = 1
+		if g.random.Intn(2) == 0 {
+			b.side = -1
+		}
+	}
+
+	player := g.player.transform
+	distance := e.minLengthFromPlayer + ORBIT_FLANK_DISTANCE_FROM_MINIMUM
+
+	// Direction the player is facing (an angle of 0 faces up)
+	angle := player.angle * math.Pi / 180.0
+	fx, fy := math.Sin(angle), -math.Cos(angle)
+
+	// Flanking position, kept within the arena
+	wsX, wsY := g.arena.Size()
+	target := &Vector{
+		x: math.Max(WINDOW_PADDING, math.Min(wsX-WINDOW_PADDING, player.x-fy*b.side*distance-fx*distance/2)),
+		y: math.Max(WINDOW_PADDING, math.Min(wsY-WINDOW_PADDING, player.y+fx*b.side*distance-fy*distance/2)),
+	}
+
+	tx, ty, length := DistanceBetweenTwoPoints(e.transform, target)
+
+	e.isRunningAway = false
+	e.isStopped = length <= e.movement.velocity
+
+	if !e.isStopped {
+		e.move(tx, ty, e.movement.velocity+0.5)
+	}
+
+	// Always faces the player
+	dx, dy, _ := DistanceBetweenTwoPoints(e.transform, player)
+
+	return dx, dy
+}
+
+func (b *flankBehaviour) CanAttack(e *Enemy) bool {
+	return true
+}
+
+// Swarm: Flocks together with other swarm enemies (separation, alignment & cohesion), while seeking the player
+type swarmBehaviour struct {
+	vx float64
+	vy float64
+}
+
+func (b *swarmBehaviour) Move(e *Enemy, g *Game) (float64, float64) {
+
+	position := e.transform
+	maxVelocity := e.movement.velocity * SWARM_MAX_VELOCITY_MODIFIER
+
+	var separationX, separationY, alignmentX, alignmentY, cohesionX, cohesionY float64
+	neighbours := 0
+
+	for _, other := range g.enemies {
+		if other == e || other.disabled {
+			continue
+		}
+
+		otherBehaviour, ok := other.behaviour.(*swarmBehaviour)
+		if !ok {
+			continue
+		}
+
+		ox := other.transform.x - position.x
+		oy := other.transform.y - position.y
+		distance := math.Hypot(ox, oy)
+
+		if distance > SWARM_NEIGHBOUR_RADIUS {
+			continue
+		}
+
+		neighbours++
+
+		alignmentX += otherBehaviour.vx
+		alignmentY += otherBehaviour.vy
+		cohesionX += ox
+		cohesionY += oy
+
+		// The closer a neighbour is, the harder it pushes away
+		if distance > 0 && distance < SWARM_SEPARATION_RADIUS {
+			separationX -= ox / distance * (SWARM_SEPARATION_RADIUS - distance) / SWARM_SEPARATION_RADIUS
+			separationY -= oy / distance * (SWARM_SEPARATION_RADIUS - distance) / SWARM_SEPARATION_RADIUS
+		}
+	}
+
+	// Seek the player, or back off when too close
+	dx, dy, length := DistanceBetweenTwoPoints(position, g.player.transform)
+	seek := 1.0
+	if length < e.minLengthFromPlayer {
+		seek = -1.0
+	}
+
+	ax := dx*seek + separationX*SWARM_SEPARATION_WEIGHT
+	ay := dy*seek + separationY*SWARM_SEPARATION_WEIGHT
+
+	if neighbours > 0 && maxVelocity > 0 {
+		ax += alignmentX / float64(neighbours) / maxVelocity * SWARM_ALIGNMENT_WEIGHT
+		ay += alignmentY / float64(neighbours) / maxVelocity * SWARM_ALIGNMENT_WEIGHT
+		ax += cohesionX / float64(neighbours) / SWARM_NEIGHBOUR_RADIUS * SWARM_COHESION_WEIGHT
+		ay += cohesionY / float64(neighbours) / SWARM_NEIGHBOUR_RADIUS * SWARM_COHESION_WEIGHT
+	}
+
+	// Steer, limited to the maximum velocity
+	b.vx += ax * SWARM_STEERING * maxVelocity
+	b.vy += ay * SWARM_STEERING * maxVelocity
+
+	if velocity := math.Hypot(b.vx, b.vy); velocity > maxVelocity {
+		b.vx *= maxVelocity / velocity
+		b.vy *= maxVelocity / velocity
+	}
+
+	position.x += b.vx
+	position.y += b.vy
+
+	e.isRunningAway = false
+	e.isStopped = false
+
+	// Always faces the player
+	return dx, dy
+}
+
+func (b *swarmBehaviour) CanAttack(e *Enemy) bool {
+	return true
+}
+
+// Gets closer to the player, backs off within the distance, and stops between the distance and distance+100
+func keepDistanceFromPlayer(e *Enemy, g *Game, distance float64) (float64, float64) {
+
+	dx, dy, length := DistanceBetweenTwoPoints(e.transform, g.player.transform)
+
+	// If the enemy is within the distance, move back by inverting it's trajectory
+	if length < distance {
+		dx *= -1
+		dy *= -1
+		e.isRunningAway = true
+		e.isStopped = false
+
+		// If it's between distance and distance+100, it stops and still shoots
+	} else if (e.isRunningAway || e.isStopped) && length < distance+100 {
+		e.isRunningAway = false
+		e.isStopped = true
+
+		// Gets closer to the player
+	} else {
+		e.isRunningAway = false
+		e.isStopped = false
+	}
+
+	// if the enemy is running away, increase it's velocity
+	velocity := e.movement.velocity
+	if e.isRunningAway {
+		velocity += 1.0
+	}
+
+	if !e.isStopped {
+		e.move(dx, dy, velocity)
+	}
+
+	return dx, dy
+}
@@ -0,0 +1,241 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"go-game-space-shooter/internal/assets"
+	"io/fs"
+	"slices"
+	"strconv"
+)
+
+// Enemy types which are always registered, based on the enemy configs
+var builtinEnemyTypes = []string{"basic", "tank", "boss"}
+
+func NewEnemyRegistry(definitions []EnemyDefinition) *EnemyRegistry {
+
+	registry := &EnemyRegistry{
+		archetypes: make(map[string]*EnemyArchetype),
+	}
+
+	// Enemy type: basic
+	basic := newBasicEnemyArchetype()
+	registry.Register(basic)
+
+	// Enemy type: tank
+	tank := *basic
+	tank.name = "tank"
+	tank.scale = 1.0
+	tank.hp *= 3.0
+	registry.Register(&tank)
+
+	// Enemy type: boss
+	boss := *basic
+	boss.name = "boss"
+	boss.spriteName = "boss"
+	boss.scale = 1.0
+	boss.hp *= 20.0
+	boss.fireRate *= 6.0
+	boss.damage *= 3.0
+	boss.isBoss = true
+	boss.phases = defaultBossPhases
+	registry.Register(&boss)
+
+	// Enemy types from the data file, built on top of their base type
+	for _, definition := range definitions {
+		baseName := definition.Base
+		if baseName == "" {
+			baseName = "basic"
+		}
+
+		registry.Register(definition.apply(registry.Get(baseName)))
+	}
+
+	return registry
+}
+
+// Adds an enemy type, replacing any existing one with the same name
+func (r *EnemyRegistry) Register(archetype *EnemyArchetype) {
+	if !r.Has(archetype.name) {
+		r.names = append(r.names, archetype.name)
+	}
+
+	r.archetypes[archetype.name] = archetype
+}
+
+func (r *EnemyRegistry) Has(name string) bool {
+	_, ok := r.archetypes[name]
+	return ok
+}
+
+func (r *EnemyRegistry) Get(name string) *EnemyArchetype {
+	archetype, ok := r.archetypes[name]
+	if !ok {
+		HandleError(errors.New("enemy type \"" + name + "\" was not found in the registry"))
+	}
+
+	return archetype
+}
+
+func (r *EnemyRegistry) GetNames() []string {
+	return slices.Clone(r.names)
+}
+
+func LoadEnemyDefinitions(files fs.FS, path string) ([]EnemyDefinition, error) {
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := struct {
+		Enemies []EnemyDefinition `json:"enemies"`
+	}{}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, errors.New("cannot decode enemies with filename \"" + path + "\": " + err.Error())
+	}
+
+	var names []string
+	for i, definition := range file.Enemies {
+		err = definition.validate()
+		if err != nil {
+			return nil, errors.New("invalid enemy " + strconv.Itoa(i+1) + " in filename \"" + path + "\": " + err.Error())
+		}
+
+		if slices.Contains(names, definition.Name) {
+			return nil, errors.New("enemy \"" + definition.Name + "\" is defined more than once in filename \"" + path + "\"")
+		}
+		names = append(names, definition.Name)
+	}
+
+	return file.Enemies, nil
+}
+
+func (d *EnemyDefinition) validate() error {
+
+	if d.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	for _, spriteName := range []string{d.Sprite, d.ProjectileSprite} {
+		if _, ok := assets.SpriteMap[spriteName]; spriteName != "" && !ok {
+			return errors.New(spriteName + " was not found in the sprite map")
+		}
+	}
+
+	if _, ok := enemyBehaviours[d.Behaviour]; d.Behaviour != "" && !ok {
+		return errors.New("unknown behaviour \"" + d.Behaviour + "\"")
+	}
+
+	if _, ok := statusEffectTypes[d.Effect]; d.Effect != "" && !ok {
+		return errors.New("unknown status effect \"" + d.Effect + "\"")
+	}
+
+	if (d.Scale != nil && *d.Scale <= 0) || (d.Hp != nil && *d.Hp <= 0) || (d.FireRate != nil && *d.FireRate <= 0) {
+		return errors.New("scale, hp and fireRate must be greater than 0")
+	}
+
+	if (d.Speed != nil && *d.Speed < 0) || (d.ProjectileSpeed != nil && *d.ProjectileSpeed < 0) || (d.ProjectileDamage != nil && *d.ProjectileDamage < 0) || (d.Points != nil && *d.Points < 0) || (d.MinDistance != nil && *d.MinDistance < 0) {
+		return errors.New("speed, projectileSpeed, projectileDamage, points and minDistance cannot be negative")
+	}
+
+	err := validateBossPhases(d.Phases)
+	if err != nil {
+		return err
+	}
+
+	if d.Emitter != nil {
+		err = d.Emitter.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.Modifiers != nil {
+		err = d.Modifiers.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Creates an enemy type from the base type, overriding only the fields set in the definition
+func (d *EnemyDefinition) apply(base *EnemyArchetype) *EnemyArchetype {
+
+	archetype := *base
+	archetype.name = d.Name
+
+	if d.Sprite != "" {
+		archetype.spriteName = d.Sprite
+	}
+	if d.ProjectileSprite != "" {
+		archetype.projectileSpriteName = d.ProjectileSprite
+	}
+	if d.Behaviour != "" {
+		archetype.behaviour = d.Behaviour
+	}
+	if d.Scale != nil {
+		archetype.scale = *d.Scale
+	}
+	if d.Hp != nil {
+		archetype.hp = *d.Hp
+	}
+	if d.Speed != nil {
+		archetype.velocity = *d.Speed
+	}
+	if d.FireRate != nil {
+		archetype.fireRate = *d.FireRate
+	}
+	if d.ProjectileSpeed != nil {
+		archetype.projectileVelocity = *d.ProjectileSpeed
+	}
+	if d.ProjectileDamage != nil {
+		archetype.damage = *d.ProjectileDamage
+	}
+	if d.Points != nil {
+		archetype.worthPoints = *d.Points
+	}
+	if d.MinDistance != nil {
+		archetype.minLengthFromPlayer = *d.MinDistance
+	}
+	if d.Boss != nil {
+		archetype.isBoss = *d.Boss
+	}
+	if d.Phases != nil {
+		archetype.phases = d.Phases
+	}
+	if d.Emitter != nil {
+		archetype.emitter = d.Emitter
+	}
+	if d.Modifiers != nil {
+		archetype.modifiers = *d.Modifiers
+	}
+	if d.Effect != "" {
+		archetype.effect = d.Effect
+	}
+
+	return &archetype
+}
+
+func newBasicEnemyArchetype() *EnemyArchetype {
+
+	// Config: Enemy Scale, HP, Fire Rate, Projectile Speed, Projectile Damage & Point Worth
+	return &EnemyArchetype{
+		name:                 "basic",
+		spriteName:           "enemy",
+		projectileSpriteName: "laser_red",
+		behaviour:            "approach",
+		scale:                Configs.EnemyScale,
+		hp:                   Configs.EnemyHp,
+		velocity:             1.0,
+		fireRate:             Configs.EnemyFireRate,
+		projectileVelocity:   Configs.EnemyProjectileSpeed,
+		damage:               Configs.EnemyProjectileDamage,
+		worthPoints:          Configs.EnemyPointWorth,
+		minLengthFromPlayer:  200.0,
+		isBoss:               false,
+	}
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/widget"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Reads the input for the menu widgets: the mouse, and the keys & gamepad buttons which move the focus
+func (u *Ui) readWidgetInput() *widget.Input {
+	cursor := GetCursorVector()
+
+	return &widget.Input{
+		CursorX:     cursor.x,
+		CursorY:     cursor.y,
+		CursorMoved: u.game.actions.gamepads.HasCursorMoved(),
+		Click:       inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft),
+		Hold:        ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft),
+		Step:        u.readFocusStep(),
+		Change:      u.readFocusChange(),
+		Confirm:     u.isFocusPressed(),
+	}
+}
+
+// Gets how far the focus moves on this update: to the next widget with the down arrow, Tab & d-pad down,
+// or to the previous one with the up arrow, Shift+Tab & d-pad up. The keys are fixed, so the menus work whatever the key bindings
+func (u *Ui) readFocusStep() int {
+
+	gamepads := u.game.actions.gamepads
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown), inpututil.IsKeyJustPressed(ebiten.KeyTab) && !shift, gamepads.IsJustPressed(ActionMoveDown):
+		return 1
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp), inpututil.IsKeyJustPressed(ebiten.KeyTab) && shift, gamepads.IsJustPressed(ActionMoveUp):
+		return -1
+	}
+
+	return 0
+}
+
+// Gets how far the focused widget changes on this update: left & right arrows, and the d-pad
+func (u *Ui) readFocusChange() int {
+
+	gamepads := u.game.actions.gamepads
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight), gamepads.IsJustPressed(ActionMoveRight):
+		return 1
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft), gamepads.IsJustPressed(ActionMoveLeft):
+		return -1
+	}
+
+	return 0
+}
+
+// Checks if the focused widget is pressed: with Enter, the confirm bindings or the gamepad
+func (u *Ui) isFocusPressed() bool {
+	return inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) || u.game.actions.IsJustPressed(ActionConfirm)
+}
@@ -2,8 +2,9 @@ package game
 
 import (
 	"go-game-space-shooter/internal/audio"
+	"go-game-space-shooter/internal/config"
 	"math/rand"
-	"strconv"
+	"path/filepath"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -12,9 +13,71 @@ import (
 var max_enemies_per_wave int
 
 func (g *Game) Update() error {
+	// Arena: Follow the window size (replays set their own)
+	if g.playback == nil {
+		g.arena.width, g.arena.height = GetWindowSize()
+	}
+
+	// Configs: Reload them if they changed
+	if g.configReloader != nil {
+		g.configReloader.Update(g)
+	}
+
+	// Clock: How many fixed ticks to simulate on this update
+	ticks := g.clock.Advance()
+
+	// Input: Read the gamepads, before the UI & player use them
+	g.actions.Update()
+
 	// UI: Update
 	g.ui.Update()
 
+	// Input: Weapon switches wait for the next tick, updates without any would lose them
+	input := g.actions.ReadInput()
+	g.queueSwitches(input)
+	input = input.withoutSwitches()
+
+	for tick := range ticks {
+		tickInput := input
+
+		// Weapon switches only apply to the first tick of the update
+		if tick == 0 {
+			tickInput = g.takeSwitches(input)
+		}
+
+		// Replay: Feed the recorded input instead of the keyboard & mouse
+		if g.playback != nil && g.state == GameStatePlaying {
+			tickInput = g.playback.Next(g.arena)
+
+			// Pause on the last frame once the replay is over
+			if tickInput == nil {
+				tickInput = g.input.withoutSwitches()
+				g.state = GameStatePaused
+			}
+		}
+
+		// Advance the simulation with the current input
+		g.Tick(tickInput)
+	}
+
+	return nil
+}
+
+// Advances the game simulation by a single tick, using the given input
+func (g *Game) Tick(input *Input) {
+	g.input = input
+
+	// Replay: Record the input of every tick played
+	if g.replay != nil && g.state == GameStatePlaying {
+		g.replay.Record(g.input, g.arena)
+	}
+
+	// World: Keep the transforms of the previous tick, to interpolate between them when drawing
+	if g.state == GameStatePlaying {
+		g.world.SaveTransforms()
+		g.runTicks++
+	}
+
 	// Player: Update
 	g.player.Update(g)
 
@@ -27,13 +90,21 @@ func (g *Game) Update() error {
 	if g.state == GameStateDeath && !g.hasSavedOnDeath {
 		g.hasSavedOnDeath = true
 
-		_, err := g.save.Save(g)
-		if err != nil {
-			HandleError(err)
+		if !g.headless {
+			g.saveOnDeath()
 		}
 	}
 
 	if g.state == GameStatePlaying {
+		// Slow-time: Enemies and their projectiles aren't updated on every tick
+		enemyTicks := g.player.effects.GetEnemyTicks()
+
+		// Enemy: Update
+		g.world.UpdateAI(g, enemyTicks)
+
+		// Collisions: Index the player, enemies and pickups at their new positions
+		g.grid.Rebuild(g)
+
 		// Pickup: Update
 		if len(g.pickups) > 0 {
 			for _, pickup := range g.pickups {
@@ -41,45 +112,41 @@ func (g *Game) Update() error {
 			}
 		}
 
-		// Enemy: Update
-		if len(g.enemies) > 0 {
-			for _, enemy := range g.enemies {
-				enemy.Update(g, g.player)
-			}
-		}
-
 		// Projectile: Update
 		if len(g.projectiles) > 0 {
 			for _, projectile := range g.projectiles {
-				projectile.Update(g)
+				if projectile.ownerTag == "enemy" {
+					for range enemyTicks {
+						projectile.Update(g)
+					}
+				} else {
+					projectile.Update(g)
+				}
 			}
 		}
 
+		// Damage Numbers: Update
+		g.updateDamageNumbers()
+
 		// Enemy: Spawn timer
 		g.enemySpawnTimer.Update()
 		if g.enemySpawnTimer.IsReady() {
 			g.enemySpawnTimer.Reset()
 
 			// If there's a boss on screen, don't spawn new enemies and don't increment current wave
-			bossPresent := false
-			if len(g.enemies) > 0 {
-				for _, enemy := range g.enemies {
-					if enemy.enemyType == "boss" {
-						bossPresent = true
-						break
-					}
-				}
-			}
-
-			if !bossPresent {
+			if !g.isBossPresent() {
 				// Only spawn enemies if the game is being actively played
 				if g.state == GameStatePlaying {
 					g.currentWave++
-					g.enemies = SpawnEnemies(g.random, g.enemies, g.currentWave, max_enemies_per_wave)
+					g.spawnWave()
+					g.player.UnlockWeapons(g.currentWave)
 				}
 			}
 		}
 
+		// Enemy: Spawn the queued enemies of the current wave
+		g.updatePendingSpawns()
+
 		// Pickup: Spawn timer
 		g.pickupSpawnTimer.Update()
 		if g.pickupSpawnTimer.IsReady() {
@@ -87,56 +154,27 @@ func (g *Game) Update() error {
 
 			// Only spawn pickups if the game is being actively played
 			if g.state == GameStatePlaying {
-				g.pickups = SpawnPickups(g.random, g.pickups, 2)
+				pickups := SpawnPickups(g.random, g.arena, g.pickupTypes, g.pickups, g.currentWave, 2)
+				for _, pickup := range pickups[len(g.pickups):] {
+					g.addPickup(pickup)
+				}
 			}
 		}
+
+		// Remove the entities disabled during this tick
+		g.removeDisabledEntities()
 	}
 
-	// A 1-second timer to check for bounds, and HP
+	// A 1-second timer for the music
 	g.oneSecondTimer.Update()
 	if g.oneSecondTimer.IsReady() {
 		g.oneSecondTimer.Reset()
 
 		// Loop music
-		if !g.music.Player.IsPlaying() {
+		if !g.headless && !g.music.Player.IsPlaying() {
 			g.music.Play()
 		}
-
-		// Check Projectiles
-		if len(g.projectiles) > 0 {
-			var tmp []*Projectile
-			for _, projectile := range g.projectiles {
-				if !projectile.IsOutOfBounds() && !projectile.disabled {
-					tmp = append(tmp, projectile)
-				}
-			}
-			g.projectiles = tmp
-		}
-
-		// Check Enemies enabled
-		if len(g.enemies) > 0 {
-			var tmp []*Enemy
-			for _, enemy := range g.enemies {
-				if !enemy.disabled {
-					tmp = append(tmp, enemy)
-				}
-			}
-			g.enemies = tmp
-		}
-
-		// Check Pickups enabled
-		if len(g.pickups) > 0 {
-			var tmp []*Pickup
-			for _, pickup := range g.pickups {
-				if !pickup.disabled {
-					tmp = append(tmp, pickup)
-				}
-			}
-			g.pickups = tmp
-		}
 	}
-
-	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
@@ -146,27 +184,17 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 	// Game State: Playing
 	if g.state == GameStatePlaying || g.state == GameStatePaused {
-		// Projectile: Draw
-		if len(g.projectiles) > 0 {
-			for _, projectile := range g.projectiles {
-				projectile.Draw(screen)
-			}
-		}
+		// World: Draw the sprites of every entity, between the last two ticks
+		alpha := g.getDrawAlpha()
+		g.world.Draw(screen, alpha)
 
 		// Player: Draw
-		g.player.Draw(screen)
+		g.player.Draw(screen, alpha)
 
 		// Enemy: Draw
 		if len(g.enemies) > 0 {
 			for _, enemy := range g.enemies {
-				enemy.Draw(screen)
-			}
-		}
-
-		// Pickup: Draw
-		if len(g.pickups) > 0 {
-			for _, pickup := range g.pickups {
-				pickup.Draw(screen)
+				enemy.Draw(screen, alpha)
 			}
 		}
 	}
@@ -175,15 +203,24 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.ui.Draw(screen)
 }
 
+// Gets how far along the next tick is, to draw between the last two ticks (the last one when the game isn't running)
+func (g *Game) getDrawAlpha() float64 {
+	if g.state != GameStatePlaying {
+		return 1.0
+	}
+
+	return g.clock.GetAlpha()
+}
+
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
 	return outsideWidth, outsideHeight
 }
 
-var Configs map[string]string
+var Configs *config.Config
 
-func NewGame(configs map[string]string) *Game {
+func NewGame(configs *config.Config, data *GameData) *Game {
 
-	Configs = configs
+	g := newGame(configs, data, NewArena(GetWindowSize()), false)
 
 	// Game music
 	music, err := audio.NewAudio("music.mp3", "mp3")
@@ -191,95 +228,142 @@ func NewGame(configs map[string]string) *Game {
 		HandleError(err)
 	}
 
-	// Config: Game Seed
-	game_seed, err := strconv.ParseInt(Configs["GAME_SEED"], 10, 64)
+	g.music = music
+	g.save = NewSave()
+
+	// Load the key bindings, stored next to the save file. Invalid bindings are kept on screen, and the game uses the defaults
+	g.actions, err = LoadActionMap(g.GetActionMapPath())
 	if err != nil {
-		HandleError(err)
+		g.actions = NewActionMap()
+		g.bindingsErr = err
 	}
 
-	// Game Seed: If 0, generate a new one everytime
-	if game_seed == 0 {
-		game_seed = time.Now().UnixNano()
-	}
+	// Attach the game UI
+	g.ui = NewUi(g)
 
-	// Config: Enemy Spawn Time
-	enemy_spawn_time, err := strconv.ParseInt(Configs["ENEMY_SPAWN_TIME"], 10, 0)
-	if err != nil {
-		HandleError(err)
-	}
+	// Config: Music Volume
+	g.music.SetVolume(Configs.MusicVolume)
+
+	// Play the music
+	g.music.Play()
+
+	// Load the Save
+	g.save.LoadSave(g, false)
+
+	// Record the first run
+	g.startReplay()
+
+	return g
+}
+
+// Creates a game which plays back a recorded replay, instead of reading the keyboard & mouse
+func NewReplayGame(replay *Replay) *Game {
 
-	// Config: Pickup Spawn Time
-	pickup_spawn_time, err := strconv.ParseInt(Configs["PICKUP_SPAWN_TIME"], 10, 0)
+	configs, err := replay.GetConfigs()
 	if err != nil {
 		HandleError(err)
 	}
 
-	// Config: Maximum Enemies per Wave
-	tmp, err := strconv.ParseInt(Configs["MAX_ENEMIES_PER_WAVE"], 10, 0)
-	if err != nil {
-		HandleError(err)
+	g := NewGame(configs, &replay.GameData)
+
+	g.playback = NewReplayPlayback(replay)
+	g.Restart()
+
+	return g
+}
+
+// Creates a game without a window, UI, audio or save file, driven only through Tick
+func NewHeadlessGame(configs *config.Config, data *GameData, width float64, height float64) *Game {
+
+	g := newGame(configs, data, NewArena(width, height), true)
+
+	g.state = GameStatePlaying
+
+	return g
+}
+
+func newGame(configs *config.Config, data *GameData, arena *Arena, headless bool) *Game {
+
+	Configs = configs
+
+	if data == nil {
+		data = &GameData{}
 	}
 
-	max_enemies_per_wave = int(tmp)
+	game_seed := getNewSeed()
+
+	// Config: Maximum Enemies per Wave
+	max_enemies_per_wave = Configs.MaxEnemiesPerWave
 
 	g := &Game{
 		// Utils
 		random: rand.New(rand.NewSource(game_seed)),
-		music:  music,
-		save:   NewSave(),
+		seed:   game_seed,
 		state:  GameStateInitial,
+		arena:  arena,
+		input:  NewInput(false, false, false, false, false, 0, 0, arena.width/2.0, 0),
+		sounds: NewSounds(headless),
 
 		// Mechanics
 		score:            NewScore(),
-		enemySpawnTimer:  NewTimer(time.Duration(enemy_spawn_time) * time.Second),
-		pickupSpawnTimer: NewTimer(time.Duration(pickup_spawn_time) * time.Second),
-
-		// Entities
-		player: NewPlayer(),
+		leaderboard:      NewLeaderboard(),
+		data:             data,
+		enemyTypes:       NewEnemyRegistry(data.Enemies),
+		pickupTypes:      NewPickupCatalogue(data.Pickups, headless),
+		grid:             NewSpatialGrid(SPATIAL_GRID_CELL_SIZE),
+		projectilePool:   NewProjectilePool(),
+		world:            NewWorld(),
+		actions:          NewActionMap(),
+		clock:            NewClock(Configs.TimeScale),
+		enemySpawnTimer:  NewTimer(time.Duration(Configs.EnemySpawnTime) * time.Second),
+		pickupSpawnTimer: NewTimer(time.Duration(Configs.PickupSpawnTime) * time.Second),
 
 		// Flags
 		hasSavedOnDeath: false,
+		headless:        headless,
 
 		// Counters
 		currentWave: 0,
+		runTicks:    0,
+		placement:   0,
 
 		// Misc.
 		oneSecondTimer: NewTimer(1000 * time.Millisecond),
 	}
 
-	// Attach the game UI
-	g.ui = NewUi(g)
+	// Entities
+	g.setPlayer(NewPlayer(arena, g.sounds))
 
 	// Trigger enemy spawner once on init
 	g.enemySpawnTimer.TriggerNow()
 
-	// Config: Music Volume
-	music_volume, err := strconv.ParseFloat(Configs["MUSIC_VOLUME"], 64)
-	if err != nil {
-		HandleError(err)
-	}
-
-	// Play the music
-	g.music.SetVolume(music_volume)
-	g.music.Play()
-
-	// Load the Save
-	g.save.LoadSave(g, false)
-
 	return g
 }
 
 // Restarts the game
 func (g *Game) Restart() {
 
-	// Reset Score
+	// Reset Seed: Replays reuse the recorded one, so every run can be reproduced
+	if g.playback != nil {
+		g.playback.Rewind()
+		g.seed = g.playback.replay.Seed
+		g.arena = NewArena(g.playback.replay.Width, g.playback.replay.Height)
+	} else {
+		g.seed = getNewSeed()
+	}
+	g.random = rand.New(rand.NewSource(g.seed))
+
+	// Reset Score: Replays don't beat the high score
 	g.score.ResetScore()
+	g.score.KeepHighScore(g.playback != nil)
 
 	// Reset Entities
-	g.player = NewPlayer()
+	g.clearProjectiles()
+	g.world.Clear()
+	g.setPlayer(NewPlayer(g.arena, g.sounds))
 	g.enemies = nil
 	g.pickups = nil
-	g.projectiles = nil
+	g.pendingSpawns = nil
 	g.damageNumbers = nil
 
 	// Reset Flags
@@ -287,6 +371,8 @@ func (g *Game) Restart() {
 
 	// Reset Counters
 	g.currentWave = 0
+	g.runTicks = 0
+	g.placement = 0
 
 	// Reset Timers
 	g.enemySpawnTimer.Reset()
@@ -297,4 +383,137 @@ func (g *Game) Restart() {
 	g.enemySpawnTimer.TriggerNow()
 
 	g.state = GameStatePlaying
+
+	// Record the new run
+	g.startReplay()
+}
+
+// Decodes the sound effects, headless games don't play any so they're skipped (and no audio context is needed)
+func NewSounds(headless bool) *Sounds {
+
+	sounds := &Sounds{
+		attacks: make(map[string]*audio.Audio),
+	}
+
+	if headless {
+		return sounds
+	}
+
+	for _, weaponType := range weaponTypes {
+		if _, ok := sounds.attacks[weaponType.audioName]; ok {
+			continue
+		}
+
+		attackAudio, err := audio.NewAudio(weaponType.audioName, "wav")
+		if err != nil {
+			HandleError(err)
+		}
+		sounds.attacks[weaponType.audioName] = attackAudio
+	}
+
+	playerHit, err := audio.NewAudio("damage2.wav", "wav")
+	if err != nil {
+		HandleError(err)
+	}
+	sounds.playerHit = playerHit
+
+	enemyHit, err := audio.NewAudio("damage1.mp3", "mp3")
+	if err != nil {
+		HandleError(err)
+	}
+	sounds.enemyHit = enemyHit
+
+	return sounds
+}
+
+// Ranks the run, saves the high score & leaderboard and writes the replay of the run.
+// Replays played back were saved when they were played, so they don't change the save file
+func (g *Game) saveOnDeath() {
+
+	if g.playback != nil {
+		return
+	}
+
+	// Leaderboard: Rank the run
+	g.placement = g.leaderboard.Add(g.newLeaderboardEntry())
+
+	_, err := g.save.Save(g)
+	if err != nil {
+		HandleError(err)
+	}
+
+	err = g.saveReplay()
+	if err != nil {
+		HandleError(err)
+	}
+}
+
+// Plays a sound effect, unless the game is running headless
+func (g *Game) PlayAudio(a *audio.Audio) {
+	if g.headless {
+		return
+	}
+
+	// Config: SFX Volume
+	a.PlayScaled(Configs.SfxVolume)
+}
+
+func (g *Game) GetScore() int64 {
+	return g.score.GetScore()
+}
+
+// Gets the path of the key bindings file, in the save folder
+func (g *Game) GetActionMapPath() string {
+	return filepath.Join(g.save.GetDirectory(), ACTION_MAP_FILE_NAME)
+}
+
+func (g *Game) GetCurrentWave() int {
+	return g.currentWave
+}
+
+func (g *Game) IsPlayerDead() bool {
+	return g.player.disabled
+}
+
+// Gets the seed for a new run
+func getNewSeed() int64 {
+
+	// Config: Game Seed
+	game_seed := Configs.GameSeed
+
+	// Game Seed: If 0, generate a new one everytime
+	if game_seed == 0 {
+		game_seed = time.Now().UnixNano()
+	}
+
+	return game_seed
+}
+
+// Adds a damage number above the top center of a collision rectangle
+func (g *Game) AddDamageNumber(damage float64, collision *CollisionRect, effect string) {
+	g.damageNumbers = append(g.damageNumbers, DamageNumber{
+		damage:      damage,
+		x:           float64(collision.x0 + (collision.x1-collision.x0)/2),
+		y:           float64(collision.y0),
+		effect:      effect,
+		ticksPassed: 0,
+	})
+}
+
+func (g *Game) updateDamageNumbers() {
+
+	if len(g.damageNumbers) > 0 {
+		var newDamageNumbers []DamageNumber
+
+		for _, damageNumber := range g.damageNumbers {
+
+			// Live for only DAMAGE_NUMBER_MAX_TICKS ticks
+			if damageNumber.ticksPassed <= DAMAGE_NUMBER_MAX_TICKS {
+				damageNumber.ticksPassed += 1
+				newDamageNumbers = append(newDamageNumbers, damageNumber)
+			}
+		}
+
+		g.damageNumbers = newDamageNumbers
+	}
 }
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/config"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	TEST_ARENA_WIDTH  = 1280
+	TEST_ARENA_HEIGHT = 720
+	TEST_SEED         = 42
+)
+
+// Creates a headless game with the default configs, seeded so every run plays the same
+func newTestGame(seed int64) *Game {
+	configs := config.NewDefaultConfig()
+	configs.GameSeed = seed
+
+	return NewHeadlessGame(configs, nil, TEST_ARENA_WIDTH, TEST_ARENA_HEIGHT)
+}
+
+// Scripted input: moves around in a square while firing at a cursor going around the player
+func getTestInput(tick int) *Input {
+	side := tick / 60 % 4
+	angle := float64(tick) * math.Pi / 90
+
+	return NewInput(side == 0, side == 2, side == 3, side == 1, true, 0, 0, TEST_ARENA_WIDTH/2+math.Cos(angle)*300, TEST_ARENA_HEIGHT/2+math.Sin(angle)*300)
+}
+
+func TestHeadlessMovement(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+	x, y := g.player.transform.x, g.player.transform.y
+
+	for range 10 {
+		g.Tick(NewInput(true, false, false, true, false, 0, 0, x, 0))
+	}
+
+	if g.player.transform.y >= y {
+		t.Errorf("moving up: y went from %v to %v", y, g.player.transform.y)
+	}
+	if g.player.transform.x <= x {
+		t.Errorf("moving right: x went from %v to %v", x, g.player.transform.x)
+	}
+
+	// The player stays within the arena
+	for range 1000 {
+		g.Tick(NewInput(true, false, false, true, false, 0, 0, x, 0))
+	}
+
+	if g.player.transform.x > TEST_ARENA_WIDTH || g.player.transform.y < 0 {
+		t.Errorf("the player left the arena, at (%v, %v)", g.player.transform.x, g.player.transform.y)
+	}
+}
+
+func TestHeadlessWaveSpawns(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+
+	if g.currentWave != 0 || len(g.enemies) != 0 {
+		t.Fatalf("expected no wave before the first tick, got wave %d with %d enemies", g.currentWave, len(g.enemies))
+	}
+
+	// The spawner is triggered on the first tick, then every ENEMY_SPAWN_TIME seconds
+	g.Tick(NewInput(false, false, false, false, false, 0, 0, 0, 0))
+	if g.currentWave != 1 || len(g.enemies) == 0 {
+		t.Fatalf("expected the first wave to spawn, got wave %d with %d enemies", g.currentWave, len(g.enemies))
+	}
+
+	for range Configs.EnemySpawnTime * SIMULATION_TPS {
+		g.Tick(NewInput(false, false, false, false, false, 0, 0, 0, 0))
+	}
+
+	if g.currentWave != 2 {
+		t.Errorf("expected the second wave after %d seconds, got wave %d", Configs.EnemySpawnTime, g.currentWave)
+	}
+}
+
+func TestHeadlessCollisions(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+	g.enemySpawnTimer.Reset()
+
+	// An enemy right above the player, which is fired at
+	enemy := NewEnemy(g.enemyTypes.Get("basic"), g.player.transform.x, g.player.transform.y-250, 0)
+	g.addEnemy(enemy)
+
+	hp := enemy.health.current
+
+	for range 2 * SIMULATION_TPS {
+		g.Tick(NewInput(false, false, false, false, true, 0, 0, enemy.transform.x, enemy.transform.y))
+
+		if enemy.health.current < hp {
+			return
+		}
+	}
+
+	t.Errorf("the enemy wasn't hit, it still has %v HP", enemy.health.current)
+}
+
+// A projectile that can't pierce only hurts one of the enemies it overlaps
+func TestHeadlessPierce(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+
+	x, y := g.player.transform.x, g.player.transform.y-250
+	a := NewEnemy(g.enemyTypes.Get("basic"), x, y, 0)
+	b := NewEnemy(g.enemyTypes.Get("basic"), x, y, 0)
+	g.addEnemy(a)
+	g.addEnemy(b)
+
+	hp := a.health.current
+
+	projectile := g.projectilePool.Get("player", g.player.attack.spriteName, x, y, 0, 0, 1, false, nil)
+	projectile.modifiers.Pierce = 0
+	g.addProjectile(projectile)
+
+	g.grid.Rebuild(g)
+	projectile.checkCollisions(g)
+
+	damaged := 0
+	for _, enemy := range []*Enemy{a, b} {
+		if enemy.health.current < hp {
+			damaged++
+		}
+	}
+
+	if damaged != 1 {
+		t.Errorf("expected 1 enemy to be damaged, got %d", damaged)
+	}
+	if !projectile.disabled {
+		t.Errorf("the projectile wasn't disabled after its hit")
+	}
+}
+
+// A fast bouncing projectile bounces off the left & right edges, instead of being removed once it crosses them
+func TestHeadlessBouncing(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+
+	x, y := 20.0, TEST_ARENA_HEIGHT/2.0
+	projectile := g.projectilePool.Get("player", g.player.attack.spriteName, x, y, 0, 40, 1, false, nil)
+	projectile.SetProjectileDirection(&Vector{x: x - 1, y: y})
+	projectile.SetModifiers(ProjectileModifiers{Bounce: 2})
+	g.addProjectile(projectile)
+
+	for range 10 * SIMULATION_TPS {
+		projectile.Update(g)
+		g.removeDisabledEntities()
+
+		if !slices.Contains(g.projectiles, projectile) {
+			break
+		}
+	}
+
+	if projectile.bounced != 2 {
+		t.Errorf("expected the projectile to bounce off both edges, it bounced %d times", projectile.bounced)
+	}
+	if slices.Contains(g.projectiles, projectile) {
+		t.Errorf("expected the projectile to leave the arena once it ran out of bounces")
+	}
+}
+
+func TestHeadlessPickups(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+	g.Tick(NewInput(false, false, false, false, false, 0, 0, 0, 0))
+
+	// A shield dropped on the player
+	pickup := NewPickup(g.pickupTypes.Get("shield"), g.player.transform.x, g.player.transform.y)
+	g.addPickup(pickup)
+
+	g.Tick(NewInput(false, false, false, false, false, 0, 0, 0, 0))
+
+	if !pickup.disabled {
+		t.Errorf("the pickup wasn't picked up")
+	}
+	if !g.player.effects.Has("shield") {
+		t.Errorf("the pickup's effect wasn't applied")
+	}
+}
+
+// Two games with the same seed & input play the same, for thousands of ticks
+func TestHeadlessDeterminism(t *testing.T) {
+
+	ticks := int(2 * time.Minute / GetTickDuration())
+
+	a := newTestGame(TEST_SEED)
+	b := newTestGame(TEST_SEED)
+
+	for tick := range ticks {
+		a.Tick(getTestInput(tick))
+		b.Tick(getTestInput(tick))
+	}
+
+	if a.score.GetScore() != b.score.GetScore() || a.currentWave != b.currentWave || len(a.enemies) != len(b.enemies) {
+		t.Errorf("the games diverged: score %d/%d, wave %d/%d, %d/%d enemies", a.score.GetScore(), b.score.GetScore(), a.currentWave, b.currentWave, len(a.enemies), len(b.enemies))
+	}
+	if a.player.transform.x != b.player.transform.x || a.player.transform.y != b.player.transform.y {
+		t.Errorf("the players diverged: (%v, %v) and (%v, %v)", a.player.transform.x, a.player.transform.y, b.player.transform.x, b.player.transform.y)
+	}
+}
+
+// Weapon switches read on updates without a tick are kept for the next one, and only applied once
+func TestPendingWeaponSwitches(t *testing.T) {
+
+	g := newTestGame(TEST_SEED)
+
+	g.queueSwitches(NewInput(false, false, false, false, false, 3, 0, 0, 0))
+	g.queueSwitches(NewInput(false, false, false, false, false, 0, 1, 0, 0))
+	g.queueSwitches(NewInput(false, false, false, false, false, 0, 1, 0, 0))
+
+	input := g.takeSwitches(NewInput(false, false, false, false, true, 0, 0, 0, 0))
+	if input.weapon != 3 || input.scroll != 2 || !input.fire {
+		t.Errorf("expected slot 3 and a scroll of 2 with the fire held, got slot %d and a scroll of %d", input.weapon, input.scroll)
+	}
+
+	input = g.takeSwitches(input)
+	if input.weapon != 0 || input.scroll != 0 {
+		t.Errorf("expected the switches to be cleared once taken, got slot %d and a scroll of %d", input.weapon, input.scroll)
+	}
+}
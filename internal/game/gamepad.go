@@ -0,0 +1,148 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// How far from the player the right stick aims
+const GAMEPAD_AIM_DISTANCE = 1000.0
+
+// How far a stick needs to lean towards a direction to move that way (so it moves in 8 directions)
+const GAMEPAD_STICK_THRESHOLD = 0.38
+
+// Buttons of the standard gamepad layout bound to each action, the left stick moves as well
+var gamepadActionButtons = [ActionCount][]ebiten.StandardGamepadButton{
+	ActionMoveUp:         {ebiten.StandardGamepadButtonLeftTop},
+	ActionMoveDown:       {ebiten.StandardGamepadButtonLeftBottom},
+	ActionMoveLeft:       {ebiten.StandardGamepadButtonLeftLeft},
+	ActionMoveRight:      {ebiten.StandardGamepadButtonLeftRight},
+	ActionFire:           {ebiten.StandardGamepadButtonFrontBottomRight},
+	ActionNextWeapon:     {ebiten.StandardGamepadButtonFrontTopRight},
+	ActionPreviousWeapon: {ebiten.StandardGamepadButtonFrontTopLeft},
+	ActionPause:          {ebiten.StandardGamepadButtonCenterRight},
+	ActionConfirm:        {ebiten.StandardGamepadButtonRightBottom},
+	ActionBack:           {ebiten.StandardGamepadButtonRightRight},
+}
+
+func NewGamepads() *Gamepads {
+	return &Gamepads{}
+}
+
+// Updates the connected gamepads, which can be plugged in and out at any time, and the direction the right stick aims at
+func (gp *Gamepads) Update() {
+
+	gp.justDisconnected = false
+	for _, id := range gp.ids {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			gp.justDisconnected = true
+		}
+	}
+
+	// Only gamepads with the standard layout are used, as their buttons are known
+	gp.ids = gp.ids[:0]
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			gp.ids = append(gp.ids, id)
+		}
+	}
+
+	// The mouse takes over aiming once it's used
+	cursor := GetCursorVector()
+	gp.cursorMoved = cursor.x != gp.cursor.x || cursor.y != gp.cursor.y
+	gp.cursor = *cursor
+
+	if gp.cursorMoved || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		gp.aim = nil
+	}
+
+	// Right stick: Aims, the last direction is kept once it's released
+	for _, id := range gp.ids {
+		x, y, ok := readStick(id, ebiten.StandardGamepadAxisRightStickHorizontal, ebiten.StandardGamepadAxisRightStickVertical)
+		if ok {
+			gp.aim = &Vector{x: x, y: y}
+			break
+		}
+	}
+}
+
+// Checks if any gamepad holds down a button of the action, or leans its left stick towards it
+func (gp *Gamepads) IsPressed(action Action) bool {
+	for _, id := range gp.ids {
+		for _, button := range gamepadActionButtons[action] {
+			if ebiten.IsStandardGamepadButtonPressed(id, button) {
+				return true
+			}
+		}
+
+		x, y, ok := readStick(id, ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisLeftStickVertical)
+		if ok && isStickTowards(action, x, y) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks if any gamepad pressed a button of the action on this update
+func (gp *Gamepads) IsJustPressed(action Action) bool {
+	for _, id := range gp.ids {
+		for _, button := range gamepadActionButtons[action] {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Gets the direction the right stick aims at (of length 1), or nil if the mouse aims
+func (gp *Gamepads) GetAim() *Vector {
+	return gp.aim
+}
+
+func (gp *Gamepads) IsConnected() bool {
+	return len(gp.ids) > 0
+}
+
+func (gp *Gamepads) IsJustDisconnected() bool {
+	return gp.justDisconnected
+}
+
+func (gp *Gamepads) HasCursorMoved() bool {
+	return gp.cursorMoved
+}
+
+// Reads a stick as a direction of length 1, if it's pushed past the dead zone
+func readStick(id ebiten.GamepadID, horizontal ebiten.StandardGamepadAxis, vertical ebiten.StandardGamepadAxis) (float64, float64, bool) {
+
+	x := ebiten.StandardGamepadAxisValue(id, horizontal)
+	y := ebiten.StandardGamepadAxisValue(id, vertical)
+	length := math.Hypot(x, y)
+
+	// Config: Gamepad Dead Zone
+	if length == 0 || length <= Configs.GamepadDeadZone {
+		return 0, 0, false
+	}
+
+	return x / length, y / length, true
+}
+
+// Checks if a stick direction moves towards the action
+func isStickTowards(action Action, x float64, y float64) bool {
+	switch action {
+	case ActionMoveUp:
+		return y < -GAMEPAD_STICK_THRESHOLD
+	case ActionMoveDown:
+		return y > GAMEPAD_STICK_THRESHOLD
+	case ActionMoveLeft:
+		return x < -GAMEPAD_STICK_THRESHOLD
+	case ActionMoveRight:
+		return x > GAMEPAD_STICK_THRESHOLD
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Gets the point to aim at from the origin: the cursor, or far along the direction of the right stick
+func (i *Input) GetAimTarget(origin *Vector) *Vector {
+	if i.aim == nil {
+		return i.cursor
+	}
+
+	return &Vector{
+		x: origin.x + i.aim.x*GAMEPAD_AIM_DISTANCE,
+		y: origin.y + i.aim.y*GAMEPAD_AIM_DISTANCE,
+	}
+}
+
+// Number keys, to switch to the weapon in each slot
+var weaponKeys = []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5, ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9}
+
+// Gets a copy of the input without the weapon switches, which only apply once
+func (i *Input) withoutSwitches() *Input {
+	input := *i
+	input.weapon = 0
+	input.scroll = 0
+
+	return &input
+}
+
+// Keeps the weapon switches of the input until a tick applies them (the last slot pressed wins, the scrolls add up)
+func (g *Game) queueSwitches(input *Input) {
+	if input.weapon != 0 {
+		g.pendingWeapon = input.weapon
+	}
+	g.pendingScroll += input.scroll
+}
+
+// Gets a copy of the input with the pending weapon switches, which are then cleared
+func (g *Game) takeSwitches(input *Input) *Input {
+	taken := *input
+	taken.weapon = g.pendingWeapon
+	taken.scroll = g.pendingScroll
+
+	g.pendingWeapon = 0
+	g.pendingScroll = 0
+
+	return &taken
+}
+
+func NewInput(up bool, down bool, left bool, right bool, fire bool, weapon int, scroll int, cursorX float64, cursorY float64) *Input {
+	return &Input{
+		up:     up,
+		down:   down,
+		left:   left,
+		right:  right,
+		fire:   fire,
+		weapon: weapon,
+		scroll: scroll,
+		cursor: &Vector{
+			x: cursorX,
+			y: cursorY,
+		},
+	}
+}
+
+// Gets the weapon slot of the number key just pressed (starting at 1), or 0 if none was
+func readWeaponSlot() int {
+	for i, key := range weaponKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// Gets the mouse wheel direction: 1 for the next weapon (scrolling down), -1 for the previous one, or 0
+func readWeaponScroll() int {
+	_, dy := ebiten.Wheel()
+
+	if dy < 0 {
+		return 1
+	} else if dy > 0 {
+		return -1
+	}
+
+	return 0
+}
@@ -0,0 +1,401 @@
+package game
+
+import (
+	"cmp"
+	"go-game-space-shooter/internal/widget"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// How many runs the leaderboard keeps
+const LEADERBOARD_SIZE = 10
+
+const LEADERBOARD_NAME_MAX_LENGTH = 12
+
+// Name of the runs which weren't given one
+const LEADERBOARD_DEFAULT_NAME = "Player"
+
+// The leaderboard screen
+const (
+	LEADERBOARD_FONT_SIZE   = 16
+	LEADERBOARD_ROW_SPACING = 8
+	LEADERBOARD_COLUMN_GAP  = 16
+)
+
+// Columns of the leaderboard screen, with their minimum widths
+var leaderboardColumns = []struct {
+	label string
+	width float64
+}{
+	{"#", 30}, {"Name", 150}, {"Score", 100}, {"Wave", 60}, {"Time", 70}, {"Seed", 190}, {"Date", 110},
+}
+
+// Save file keys: each entry is stored as LEADERBOARD_<rank>_<field>
+const (
+	LEADERBOARD_SAVE_PREFIX = "LEADERBOARD_"
+	PLAYER_NAME_SAVE_KEY    = "PLAYER_NAME"
+)
+
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{
+		entries: []LeaderboardEntry{},
+		name:    LEADERBOARD_DEFAULT_NAME,
+	}
+}
+
+// Creates an entry for the current run, named after the last run ranked
+func (g *Game) newLeaderboardEntry() LeaderboardEntry {
+	return LeaderboardEntry{
+		name:     g.leaderboard.name,
+		score:    g.score.GetScore(),
+		wave:     g.currentWave,
+		duration: time.Duration(g.runTicks) * GetTickDuration(),
+		seed:     g.seed,
+		date:     time.Now(),
+	}
+}
+
+// Ranks a run, and gets where it placed (starting at 1), or 0 if it didn't make it.
+// Runs without points aren't ranked, and a run tied with another one places below it
+func (l *Leaderboard) Add(entry LeaderboardEntry) int {
+
+	if entry.score <= 0 {
+		return 0
+	}
+
+	i := 0
+	for i < len(l.entries) && l.entries[i].score >= entry.score {
+		i++
+	}
+
+	if i >= LEADERBOARD_SIZE {
+		return 0
+	}
+
+	l.entries = slices.Insert(l.entries, i, entry)
+	if len(l.entries) > LEADERBOARD_SIZE {
+		l.entries = l.entries[:LEADERBOARD_SIZE]
+	}
+
+	return i + 1
+}
+
+// Names the entry at a placement (starting at 1), the name is kept for the next runs
+func (l *Leaderboard) Rename(placement int, name string) {
+
+	name = SanitizeLeaderboardName(name)
+	l.name = name
+
+	if placement >= 1 && placement <= len(l.entries) {
+		l.entries[placement-1].name = name
+	}
+}
+
+func (l *Leaderboard) GetEntries() []LeaderboardEntry {
+	return l.entries
+}
+
+// Gets the name of the last run ranked
+func (l *Leaderboard) GetName() string {
+	return l.name
+}
+
+// Loads the entries & the last name from the save file data, entries which can't be read are skipped
+func (l *Leaderboard) Load(data map[string]string) {
+
+	l.entries = l.entries[:0]
+
+	if name, ok := data[PLAYER_NAME_SAVE_KEY]; ok {
+		l.name = SanitizeLeaderboardName(name)
+	}
+
+	for rank := 1; rank <= LEADERBOARD_SIZE; rank++ {
+		entry, ok := parseLeaderboardEntry(data, getLeaderboardSaveKey(rank))
+		if ok {
+			l.entries = append(l.entries, entry)
+		}
+	}
+
+	// The save file could have been edited by hand
+	slices.SortStableFunc(l.entries, func(a LeaderboardEntry, b LeaderboardEntry) int {
+		return cmp.Compare(b.score, a.score)
+	})
+}
+
+// Stores the entries & the last name in the save file data, replacing the ones stored before
+func (l *Leaderboard) Store(data map[string]string) {
+
+	for key := range data {
+		if strings.HasPrefix(key, LEADERBOARD_SAVE_PREFIX) {
+			delete(data, key)
+		}
+	}
+
+	data[PLAYER_NAME_SAVE_KEY] = l.name
+
+	for i, entry := range l.entries {
+		prefix := getLeaderboardSaveKey(i + 1)
+
+		data[prefix+"NAME"] = entry.name
+		data[prefix+"SCORE"] = strconv.FormatInt(entry.score, 10)
+		data[prefix+"WAVE"] = strconv.Itoa(entry.wave)
+		data[prefix+"DURATION"] = entry.duration.String()
+		data[prefix+"SEED"] = strconv.FormatInt(entry.seed, 10)
+		data[prefix+"DATE"] = entry.date.Format(time.RFC3339)
+	}
+}
+
+// Keeps letters, digits, spaces, "-", "_" and ".", up to the maximum length. Empty names get the default one
+func SanitizeLeaderboardName(name string) string {
+
+	runes := []rune{}
+	for _, r := range strings.TrimSpace(name) {
+		if len(runes) == LEADERBOARD_NAME_MAX_LENGTH {
+			break
+		}
+
+		if isLeaderboardNameRune(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	name = strings.TrimSpace(string(runes))
+	if name == "" {
+		return LEADERBOARD_DEFAULT_NAME
+	}
+
+	return name
+}
+
+func isLeaderboardNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" -_.", r)
+}
+
+// Formats a run duration as minutes & seconds (e.g. "4:05")
+func FormatRunDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return strconv.Itoa(seconds/60) + ":" + strconv.Itoa(seconds%60/10) + strconv.Itoa(seconds%10)
+}
+
+func getLeaderboardSaveKey(rank int) string {
+	return LEADERBOARD_SAVE_PREFIX + strconv.Itoa(rank) + "_"
+}
+
+func parseLeaderboardEntry(data map[string]string, prefix string) (LeaderboardEntry, bool) {
+
+	name, ok := data[prefix+"NAME"]
+	if !ok {
+		return LeaderboardEntry{}, false
+	}
+
+	score, err := strconv.ParseInt(data[prefix+"SCORE"], 10, 64)
+	if err != nil {
+		return LeaderboardEntry{}, false
+	}
+
+	wave, err := strconv.Atoi(data[prefix+"WAVE"])
+	if err != nil {
+		return LeaderboardEntry{}, false
+	}
+
+	duration, err := time.ParseDuration(data[prefix+"DURATION"])
+	if err != nil {
+		return LeaderboardEntry{}, false
+	}
+
+	seed, err := strconv.ParseInt(data[prefix+"SEED"], 10, 64)
+	if err != nil {
+		return LeaderboardEntry{}, false
+	}
+
+	date, err := time.Parse(time.RFC3339, data[prefix+"DATE"])
+	if err != nil {
+		return LeaderboardEntry{}, false
+	}
+
+	return LeaderboardEntry{
+		name:     SanitizeLeaderboardName(name),
+		score:    score,
+		wave:     wave,
+		duration: duration,
+		seed:     seed,
+		date:     date,
+	}, true
+}
+
+// Creates the leaderboard screen from the current entries
+func (u *Ui) newLeaderboardMenu() *widget.Panel {
+
+	rows := widget.NewList(LEADERBOARD_ROW_SPACING)
+
+	header := []string{}
+	for _, column := range leaderboardColumns {
+		header = append(header, column.label)
+	}
+	rows.Children = append(rows.Children, u.newLeaderboardRow(header, &u.theme.LabelColor))
+
+	entries := u.game.leaderboard.GetEntries()
+	for i, entry := range entries {
+		rows.Children = append(rows.Children, u.newLeaderboardRow([]string{
+			strconv.Itoa(i + 1),
+			entry.name,
+			strconv.FormatInt(entry.score, 10),
+			strconv.Itoa(entry.wave),
+			FormatRunDuration(entry.duration),
+			strconv.FormatInt(entry.seed, 10),
+			entry.date.Format("2006-01-02"),
+		}, nil))
+	}
+
+	if len(entries) == 0 {
+		empty := widget.NewLabel("No runs yet")
+		empty.Color = &u.theme.LabelColor
+		rows.Children = append(rows.Children, empty)
+	}
+
+	rows.Children = append(rows.Children, widget.NewButton("Back", u.closeLeaderboard))
+
+	return u.newSettingsPanel("Leaderboard", rows)
+}
+
+// A row of the leaderboard screen, a label for each column (in the theme's text color if nil)
+func (u *Ui) newLeaderboardRow(cells []string, clr *color.RGBA) *widget.List {
+
+	row := widget.NewRow(LEADERBOARD_COLUMN_GAP)
+
+	for i, cell := range cells {
+		label := widget.NewLabel(cell)
+		label.Size = LEADERBOARD_FONT_SIZE
+		label.MinWidth = leaderboardColumns[i].width
+		label.Color = clr
+
+		row.Children = append(row.Children, label)
+	}
+
+	return row
+}
+
+func (u *Ui) openLeaderboard() {
+	u.leaderboardMenu = u.newLeaderboardMenu()
+	u.screen = UiScreenLeaderboard
+}
+
+func (u *Ui) closeLeaderboard() {
+	u.screen = UiScreenNone
+}
+
+func (u *Ui) updateLeaderboard(input *widget.Input) {
+
+	// Go back
+	if u.game.actions.IsJustPressed(ActionBack) {
+		u.closeLeaderboard()
+		return
+	}
+
+	u.updateMenu(u.leaderboardMenu, input)
+}
+
+func (u *Ui) drawLeaderboard(screen *ebiten.Image) {
+	u.layoutMenu(u.leaderboardMenu)
+	u.leaderboardMenu.Draw(screen, u.theme)
+}
+
+// Starts entering the name of the run which just placed on the leaderboard, from the last name entered
+func (u *Ui) startNaming() {
+	u.naming = true
+	u.name = u.game.leaderboard.GetName()
+}
+
+// Types the name of the run: Enter (or the gamepad's confirm) names it and saves, Escape keeps the last name.
+// The keys are fixed, as the confirm bindings could be typed (e.g. Space)
+func (u *Ui) updateNaming() {
+
+	gamepads := u.game.actions.gamepads
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter), gamepads.IsJustPressed(ActionConfirm):
+		u.game.leaderboard.Rename(u.game.placement, u.name)
+
+		_, err := u.game.save.Save(u.game)
+		if err != nil {
+			HandleError(err)
+		}
+
+		u.naming = false
+		return
+
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape), gamepads.IsJustPressed(ActionBack):
+		u.naming = false
+		return
+	}
+
+	runes := []rune(u.name)
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+	}
+
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if len(runes) < LEADERBOARD_NAME_MAX_LENGTH && isLeaderboardNameRune(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	u.name = string(runes)
+}
+
+// Draws where the run placed on the leaderboard, and the name being entered in place of the death menu
+func (u *Ui) drawPlacement(screen *ebiten.Image) {
+	wsX, wsY := GetWindowSize()
+
+	op := &text.DrawOptions{}
+	u.font.Size = 20
+	op.ColorScale.Reset()
+	op.PrimaryAlign = text.AlignCenter
+
+	str := "Not on the leaderboard"
+	switch {
+	case u.game.placement == 1:
+		str = "New high score! #1 on the leaderboard"
+		op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
+	case u.game.placement > 1:
+		str = "You placed #" + strconv.Itoa(u.game.placement) + " on the leaderboard"
+		op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
+	default:
+		op.ColorScale.Scale(200/255.0, 200/255.0, 200/255.0, 255/255.0)
+	}
+
+	op.GeoM.Translate(wsX/2.0, wsY/2.0+40)
+	text.Draw(screen, str, u.font, op)
+	op.GeoM.Reset()
+
+	if !u.naming {
+		return
+	}
+
+	u.font.Size = 24
+	op.ColorScale.Reset()
+	op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
+
+	str = "Name: " + u.name + "_"
+
+	op.GeoM.Translate(wsX/2.0, wsY-WINDOW_PADDING-100)
+	text.Draw(screen, str, u.font, op)
+	op.GeoM.Reset()
+
+	u.font.Size = 16
+	op.ColorScale.Reset()
+	op.ColorScale.Scale(200/255.0, 200/255.0, 200/255.0, 255/255.0)
+
+	op.GeoM.Translate(wsX/2.0, wsY-WINDOW_PADDING-60)
+	text.Draw(screen, "Enter to confirm, Escape to skip", u.font, op)
+	op.GeoM.Reset()
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLeaderboardEntry(name string, score int64) LeaderboardEntry {
+	return LeaderboardEntry{
+		name:     name,
+		score:    score,
+		wave:     int(score / 100),
+		duration: time.Duration(score) * time.Second,
+		seed:     score * 7,
+		date:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func getLeaderboardNames(l *Leaderboard) []string {
+	names := []string{}
+	for _, entry := range l.GetEntries() {
+		names = append(names, entry.name)
+	}
+
+	return names
+}
+
+// Runs are ranked by score, and a run tied with another one places below it
+func TestLeaderboardAdd(t *testing.T) {
+
+	l := NewLeaderboard()
+
+	placements := []struct {
+		name      string
+		score     int64
+		placement int
+	}{
+		{"first", 200, 1},
+		{"best", 300, 1},
+		{"last", 100, 3},
+		{"tied", 200, 3},
+		{"none", 0, 0},
+	}
+
+	for _, p := range placements {
+		placement := l.Add(newTestLeaderboardEntry(p.name, p.score))
+		if placement != p.placement {
+			t.Errorf("%s: expected to place #%d, got #%d", p.name, p.placement, placement)
+		}
+	}
+
+	expected := []string{"best", "first", "tied", "last"}
+	names := getLeaderboardNames(l)
+
+	if len(names) != len(expected) {
+		t.Fatalf("expected the entries %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected the entries %v, got %v", expected, names)
+			break
+		}
+	}
+}
+
+// The leaderboard keeps the best runs, up to its size
+func TestLeaderboardSize(t *testing.T) {
+
+	l := NewLeaderboard()
+
+	for i := range LEADERBOARD_SIZE {
+		l.Add(newTestLeaderboardEntry("run", int64(i+1)*100))
+	}
+
+	// Below every run of a full leaderboard, or tied with the last one
+	if placement := l.Add(newTestLeaderboardEntry("worst", 50)); placement != 0 {
+		t.Errorf("expected a run below the others not to place, got #%d", placement)
+	}
+	if placement := l.Add(newTestLeaderboardEntry("tied", 100)); placement != 0 {
+		t.Errorf("expected a run tied with the last one not to place, got #%d", placement)
+	}
+
+	if placement := l.Add(newTestLeaderboardEntry("better", 150)); placement != LEADERBOARD_SIZE {
+		t.Errorf("expected a run above the last one to place #%d, got #%d", LEADERBOARD_SIZE, placement)
+	}
+
+	entries := l.GetEntries()
+	if len(entries) != LEADERBOARD_SIZE {
+		t.Fatalf("expected %d entries, got %d", LEADERBOARD_SIZE, len(entries))
+	}
+	if entries[0].score != int64(LEADERBOARD_SIZE)*100 || entries[len(entries)-1].name != "better" {
+		t.Errorf("expected the lowest run to be dropped, got %v", getLeaderboardNames(l))
+	}
+}
+
+// Renaming an entry keeps the name for the next runs
+func TestLeaderboardRename(t *testing.T) {
+
+	l := NewLeaderboard()
+	l.Add(newTestLeaderboardEntry(l.GetName(), 100))
+
+	l.Rename(1, "  Ace  ")
+	if l.GetEntries()[0].name != "Ace" || l.GetName() != "Ace" {
+		t.Errorf("expected the entry and the next runs to be named Ace, got %q and %q", l.GetEntries()[0].name, l.GetName())
+	}
+
+	// Out of range placements only keep the name
+	l.Rename(0, "Next")
+	if l.GetEntries()[0].name != "Ace" || l.GetName() != "Next" {
+		t.Errorf("expected only the next runs to be named Next, got %q and %q", l.GetEntries()[0].name, l.GetName())
+	}
+}
+
+func TestSanitizeLeaderboardName(t *testing.T) {
+
+	names := map[string]string{
+		"Ace":                   "Ace",
+		"  spaced out  ":        "spaced out",
+		"a-b_c.d":               "a-b_c.d",
+		"no=equals\n\"quotes\"": "noequalsquot",
+		"averyveryverylongname": "averyveryver",
+		"Zoë 42":                "Zoë 42",
+		"":                      LEADERBOARD_DEFAULT_NAME,
+		"#$%":                   LEADERBOARD_DEFAULT_NAME,
+	}
+
+	for name, expected := range names {
+		sanitized := SanitizeLeaderboardName(name)
+		if sanitized != expected {
+			t.Errorf("expected %q to be sanitized to %q, got %q", name, expected, sanitized)
+		}
+	}
+}
+
+func TestFormatRunDuration(t *testing.T) {
+
+	durations := map[time.Duration]string{
+		0:                                      "0:00",
+		5 * time.Second:                        "0:05",
+		4*time.Minute + 5*time.Second:          "4:05",
+		61*time.Minute + 1500*time.Millisecond: "61:01",
+	}
+
+	for d, expected := range durations {
+		formatted := FormatRunDuration(d)
+		if formatted != expected {
+			t.Errorf("expected %v to be formatted as %q, got %q", d, expected, formatted)
+		}
+	}
+}
+
+// The entries & the last name are written to the save file, and read back the same
+func TestLeaderboardSaveRoundTrip(t *testing.T) {
+
+	save := &Save{path: t.TempDir(), filename: "test.save", data: make(map[string]any)}
+
+	g := newTestGame(TEST_SEED)
+	g.save = save
+	g.leaderboard.Add(newTestLeaderboardEntry("second", 100))
+	g.leaderboard.Add(newTestLeaderboardEntry("first", 300))
+	g.leaderboard.Rename(1, "winner")
+
+	_, err := g.save.Save(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newTestGame(TEST_SEED)
+	loaded.save = &Save{path: save.path, filename: save.filename, data: make(map[string]any)}
+	loaded.save.LoadSave(loaded, false)
+
+	if loaded.leaderboard.GetName() != "winner" {
+		t.Errorf("expected the last name to be winner, got %q", loaded.leaderboard.GetName())
+	}
+
+	expected := g.leaderboard.GetEntries()
+	entries := loaded.leaderboard.GetEntries()
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+
+	for i, entry := range entries {
+		e := expected[i]
+		if entry.name != e.name || entry.score != e.score || entry.wave != e.wave || entry.duration != e.duration || entry.seed != e.seed || !entry.date.Equal(e.date) {
+			t.Errorf("entry %d: expected %+v, got %+v", i+1, e, entry)
+		}
+	}
+}
@@ -1,114 +1,62 @@
 package game
 
 import (
-	"go-game-space-shooter/internal/assets"
-	"go-game-space-shooter/internal/audio"
-	"image"
 	"image/color"
 	"math/rand"
-
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
+	"slices"
 )
 
-func NewPickup(effectName string, effectAmount float64, spriteName string, x float64, y float64, audio *audio.Audio) *Pickup {
-	sprite, err := assets.NewSprite(spriteName)
-	if err != nil {
-		HandleError(err)
-	}
+// Seconds before despawning when pickups start blinking
+const PICKUP_BLINK_SECONDS = 3.0
 
+func NewPickup(pickupType *PickupType, x float64, y float64) *Pickup {
 	pickup := Pickup{
-		position: &Vector{
-			x:     x,
-			y:     y,
-			angle: 0,
-			scale: 1,
+		Entity: &Entity{
+			transform: &Vector{
+				x:     x,
+				y:     y,
+				angle: 0,
+				scale: 1,
+			},
+			renderer: NewSpriteRenderer(pickupType.spriteName, SPRITE_LAYER_PICKUPS),
+			collider: NewColliderComponent(color.RGBA{255, 255, 0, 255}),
 		},
-		sprite:       sprite,
-		audio:        audio,
-		effectName:   effectName,
-		effectAmount: effectAmount,
+		pickupType: pickupType,
+	}
+
+	// Pickups sharing a sprite are told apart by their tint
+	if pickupType.tint != nil {
+		pickup.renderer.tint = pickupType.tint
 	}
 
-	// Update collision rectangle
-	x0, y0, x1, y1 := GetSpriteRectCoords(pickup.position, pickup.sprite, pickup.position.scale)
-	pickup.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
+	// Despawn timer
+	if pickupType.lifetime > 0 {
+		pickup.lifetime = NewTimer(secondsToDuration(pickupType.lifetime))
+	}
 
 	return &pickup
 }
 
-func SpawnPickups(random *rand.Rand, pickups []*Pickup, max int) []*Pickup {
-
-	var spawn_types = make([]map[string]any, 0)
-
-	// Spawn type: health
-	spawn_types = append(spawn_types, map[string]any{
-		"typeName":    "health",
-		"amount":      20.0,
-		"sprite":      "pill_blue",
-		"audio":       "pickup.wav",
-		"audioType":   "wav",
-		"audioVolume": 0.5,
-	})
-
-	// Spawn type: damage
-	spawn_types = append(spawn_types, map[string]any{
-		"typeName":    "damage",
-		"amount":      1.1,
-		"sprite":      "bolt_bronze",
-		"audio":       "pickup.wav",
-		"audioType":   "wav",
-		"audioVolume": 0.5,
-	})
-
-	// Spawn type: critical_modifier
-	spawn_types = append(spawn_types, map[string]any{
-		"typeName":    "critical_modifier",
-		"amount":      0.5,
-		"sprite":      "blue_box_bolt",
-		"audio":       "pickup.wav",
-		"audioType":   "wav",
-		"audioVolume": 0.5,
-	})
-
-	// Spawn type: critical_chance
-	spawn_types = append(spawn_types, map[string]any{
-		"typeName":    "critical_chance",
-		"amount":      5.0,
-		"sprite":      "blue_box_star",
-		"audio":       "pickup.wav",
-		"audioType":   "wav",
-		"audioVolume": 0.5,
-	})
+func SpawnPickups(random *rand.Rand, arena *Arena, catalogue *PickupCatalogue, pickups []*Pickup, currentWave int, max int) []*Pickup {
 
 	const OFFSET float64 = 200.0
 
 	qty := random.Intn(max) + 1
-	wsX, wsY := GetWindowSize()
+	wsX, wsY := arena.Size()
 
 	for range qty {
 
 		// Get random type to spawn
-		spawn_type := spawn_types[random.Intn(len(spawn_types))]
+		pickupType := catalogue.Pick(random, pickups, currentWave)
+		if pickupType == nil {
+			break
+		}
 
 		// Get random position for spawn
 		eX := random.Intn(int(wsX-OFFSET*2)) + int(OFFSET) // Generate an integer number between [OFFSET] and [wsX-OFFSET]
 		eY := random.Intn(int(wsY-OFFSET*2)) + int(OFFSET) // Generate an integer number between [OFFSET] and [wsY-OFFSET]
 
-		spawnAudio, err := audio.NewAudio(spawn_type["audio"].(string), spawn_type["audioType"].(string))
-		if err != nil {
-			HandleError(err)
-		}
-		spawnAudio.SetVolume(spawn_type["audioVolume"].(float64))
-
-		pickups = append(pickups, NewPickup(
-			spawn_type["typeName"].(string),
-			spawn_type["amount"].(float64),
-			spawn_type["sprite"].(string),
-			float64(eX),
-			float64(eY),
-			spawnAudio,
-		))
+		pickups = append(pickups, NewPickup(pickupType, float64(eX), float64(eY)))
 	}
 
 	return pickups
@@ -120,30 +68,16 @@ func (p *Pickup) Update(g *Game) {
 		return
 	}
 
-	p.checkCollisions(g)
-}
-
-func (p *Pickup) Draw(screen *ebiten.Image) {
-
+	// Despawn once the lifetime is over
+	p.updateLifetime()
 	if p.disabled {
 		return
 	}
 
-	op := &ebiten.DrawImageOptions{}
-
-	p.sprite.Rotate(op, p.position.angle)
-	p.sprite.Scale(op, p.position.scale)
-	p.sprite.Translate(op, p.position.scale, p.position.x, p.position.y)
-
-	screen.DrawImage(p.sprite.Image, op)
+	// Blink when about to despawn
+	p.renderer.hidden = p.lifetime != nil && p.lifetime.GetRemainingSeconds() < PICKUP_BLINK_SECONDS && int(p.lifetime.GetRemainingSeconds()*8)%2 == 0
 
-	op.GeoM.Reset()
-
-	// Config: Draw Colission Rects
-	if Configs["DRAW_COLLISION_RECTS"] == "1" {
-		// Draw collision rectangle
-		vector.StrokeRect(screen, float32(p.collision.x0), float32(p.collision.y0), float32(p.collision.x1-p.collision.x0), float32(p.collision.y1-p.collision.y0), 1.0, color.RGBA{255, 255, 0, 255}, true)
-	}
+	p.checkCollisions(g)
 }
 
 func (p *Pickup) checkCollisions(g *Game) {
@@ -152,34 +86,77 @@ func (p *Pickup) checkCollisions(g *Game) {
 		return
 	}
 
-	srcRect := image.Rect(p.collision.x0, p.collision.y0, p.collision.x1, p.collision.y1)
-
 	// Check collisions with Player
-	if !g.player.disabled && srcRect.Overlaps(image.Rect(g.player.character.position.collision.x0, g.player.character.position.collision.y0, g.player.character.position.collision.x1, g.player.character.position.collision.y1)) {
+	touched := false
+	g.grid.QueryShape(p.collider.shape, func(c *Collider) bool {
+		touched = c.player != nil && !c.player.disabled
+		return !touched
+	})
+
+	if touched {
 
 		// Play the audio
-		p.audio.Play()
+		g.PlayAudio(p.pickupType.audio)
+
+		effect := p.pickupType.effect
 
 		// Decide what to do based on effect type
-		switch p.effectName {
+		switch effect.Type {
 		case "health":
 			// Add to player's HP
-			g.player.OffsetHp(p.effectAmount)
-
-		case "damage":
-			// Add to player's HP
-			g.player.attack.damage *= p.effectAmount
-
-		case "critical_chance":
-			// Add to player's attack critical chance
-			g.player.attack.criticalChance += p.effectAmount
-
-		case "critical_modifier":
-			// Add to player's attack critical modifier
-			g.player.attack.criticalModifier += p.effectAmount
+			g.player.OffsetHp(effect.Amount)
+
+		case "weapon":
+			// Unlock the player's next weapon
+			g.player.UnlockNextWeapon()
+
+		default:
+			// Apply a timed status effect, for the pickup's duration (or the effect's default duration)
+			if slices.Contains(pickupStatusEffects, effect.Type) {
+				g.player.effects.Apply(effect.Type, effect.Amount)
+				break
+			}
+
+			// Permanently upgrade all of the player's weapons
+			for _, weapon := range g.player.weapons {
+				effect.apply(weapon.attack)
+			}
 		}
 
 		// Disable the pickup
 		p.disabled = true
 	}
 }
+
+func (e *PickupEffect) apply(attack *Attack) {
+
+	switch e.Type {
+	case "damage":
+		// Multiply attack damage
+		attack.damage *= e.Amount
+
+	case "critical_chance":
+		// Add to attack critical chance
+		attack.criticalChance += e.Amount
+
+	case "critical_modifier":
+		// Add to attack critical modifier
+		attack.criticalModifier += e.Amount
+
+	case "homing":
+		// Add to projectile turn rate
+		attack.modifiers = attack.modifiers.Combine(ProjectileModifiers{Homing: e.Amount})
+
+	case "piercing":
+		// Add to the enemies projectiles go through
+		attack.modifiers = attack.modifiers.Combine(ProjectileModifiers{Pierce: int(e.Amount)})
+
+	case "bouncing":
+		// Add to projectile bounces off the arena edges
+		attack.modifiers = attack.modifiers.Combine(ProjectileModifiers{Bounce: int(e.Amount)})
+
+	case "splash":
+		// Set projectile splash radius, if it's larger
+		attack.modifiers = attack.modifiers.Combine(ProjectileModifiers{Splash: e.Amount})
+	}
+}
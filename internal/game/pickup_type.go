@@ -0,0 +1,259 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"go-game-space-shooter/internal/assets"
+	"go-game-space-shooter/internal/audio"
+	"image/color"
+	"io/fs"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Effects of the permanent pickups
+var pickupEffects = []string{"health", "damage", "critical_chance", "critical_modifier", "homing", "piercing", "bouncing", "splash", "weapon"}
+
+// Status effects the timed pickups can apply to the player (debuffs like "slowed" are left to the enemies)
+var pickupStatusEffects = []string{"rapid_fire", "shield", "double_damage", "slow_time"}
+
+// Pickup types which are always in the catalogue, unless replaced by the data file
+var builtinPickupTypes = []PickupDefinition{
+	{Name: "health", Sprite: "pill_blue", Weight: floatPointer(3), Effect: PickupEffect{Type: "health", Amount: 20.0}},
+	{Name: "damage", Sprite: "bolt_bronze", Weight: floatPointer(2), Effect: PickupEffect{Type: "damage", Amount: 1.1}},
+	{Name: "critical_modifier", Sprite: "blue_box_bolt", Weight: floatPointer(2), Effect: PickupEffect{Type: "critical_modifier", Amount: 0.5}},
+	{Name: "critical_chance", Sprite: "blue_box_star", Weight: floatPointer(2), Effect: PickupEffect{Type: "critical_chance", Amount: 5.0}},
+	{Name: "homing", Sprite: "bolt_bronze", Tint: []int{120, 255, 120}, MinWave: 3, Effect: PickupEffect{Type: "homing", Amount: 2.0}},
+	{Name: "piercing", Sprite: "bolt_bronze", Tint: []int{120, 200, 255}, MinWave: 3, Effect: PickupEffect{Type: "piercing", Amount: 1.0}},
+	{Name: "bouncing", Sprite: "blue_box_bolt", Tint: []int{255, 220, 120}, MinWave: 3, Effect: PickupEffect{Type: "bouncing", Amount: 1.0}},
+	{Name: "splash", Sprite: "blue_box_star", Tint: []int{255, 120, 120}, MinWave: 3, Effect: PickupEffect{Type: "splash", Amount: 60.0}},
+	{Name: "weapon", Sprite: "blue_box_bolt", Tint: []int{200, 120, 255}, MinWave: 2, MaxConcurrent: 1, Lifetime: 20, Effect: PickupEffect{Type: "weapon"}},
+	{Name: "rapid_fire", Sprite: "bolt_bronze", Tint: []int{255, 200, 0}, MinWave: 2, MaxConcurrent: 1, Lifetime: 15, Effect: PickupEffect{Type: "rapid_fire"}},
+	{Name: "shield", Sprite: "pill_blue", Tint: []int{10, 191, 245}, MinWave: 2, MaxConcurrent: 1, Lifetime: 15, Effect: PickupEffect{Type: "shield"}},
+	{Name: "double_damage", Sprite: "bolt_bronze", Tint: []int{255, 80, 80}, MinWave: 2, MaxConcurrent: 1, Lifetime: 15, Effect: PickupEffect{Type: "double_damage"}},
+	{Name: "slow_time", Sprite: "blue_box_star", Tint: []int{180, 120, 255}, MinWave: 4, MaxConcurrent: 1, Lifetime: 15, Effect: PickupEffect{Type: "slow_time"}},
+}
+
+// Creates the pickup types, headless games don't play their audio so it isn't decoded
+func NewPickupCatalogue(definitions []PickupDefinition, headless bool) *PickupCatalogue {
+
+	catalogue := &PickupCatalogue{
+		types: make(map[string]*PickupType),
+	}
+
+	// Audio is decoded once, and shared by the pickup types using the same file & volume
+	sounds := make(map[string]*audio.Audio)
+
+	for _, definition := range slices.Concat(builtinPickupTypes, definitions) {
+		pickupType := definition.build()
+
+		if headless {
+			catalogue.Register(pickupType)
+			continue
+		}
+
+		key := pickupType.audioName + "@" + strconv.FormatFloat(pickupType.audioVolume, 'f', -1, 64)
+
+		sound, ok := sounds[key]
+		if !ok {
+			var err error
+			sound, err = audio.NewAudio(pickupType.audioName, getAudioType(pickupType.audioName))
+			if err != nil {
+				HandleError(err)
+			}
+			sound.SetVolume(pickupType.audioVolume)
+
+			sounds[key] = sound
+		}
+		pickupType.audio = sound
+
+		catalogue.Register(pickupType)
+	}
+
+	return catalogue
+}
+
+// Adds a pickup type, replacing any existing one with the same name
+func (c *PickupCatalogue) Register(pickupType *PickupType) {
+	if _, ok := c.types[pickupType.name]; !ok {
+		c.names = append(c.names, pickupType.name)
+	}
+
+	c.types[pickupType.name] = pickupType
+}
+
+func (c *PickupCatalogue) Get(name string) *PickupType {
+	pickupType, ok := c.types[name]
+	if !ok {
+		HandleError(errors.New("pickup type \"" + name + "\" was not found in the catalogue"))
+	}
+
+	return pickupType
+}
+
+// Picks a random pickup type by weight, out of the ones available on the wave and below their max concurrent count.
+// Returns nil if none are available
+func (c *PickupCatalogue) Pick(random *rand.Rand, pickups []*Pickup, wave int) *PickupType {
+
+	var available []*PickupType
+	total := 0.0
+
+	for _, name := range c.names {
+		pickupType := c.types[name]
+
+		if pickupType.weight <= 0 || wave < pickupType.minWave {
+			continue
+		}
+
+		if pickupType.maxConcurrent > 0 && countPickups(pickups, pickupType) >= pickupType.maxConcurrent {
+			continue
+		}
+
+		available = append(available, pickupType)
+		total += pickupType.weight
+	}
+
+	if len(available) == 0 {
+		return nil
+	}
+
+	roll := random.Float64() * total
+	for _, pickupType := range available {
+		roll -= pickupType.weight
+		if roll < 0 {
+			return pickupType
+		}
+	}
+
+	return available[len(available)-1]
+}
+
+func LoadPickupDefinitions(files fs.FS, path string) ([]PickupDefinition, error) {
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := struct {
+		Pickups []PickupDefinition `json:"pickups"`
+	}{}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, errors.New("cannot decode pickups with filename \"" + path + "\": " + err.Error())
+	}
+
+	var names []string
+	for i, definition := range file.Pickups {
+		err = definition.validate()
+		if err != nil {
+			return nil, errors.New("invalid pickup " + strconv.Itoa(i+1) + " in filename \"" + path + "\": " + err.Error())
+		}
+
+		if slices.Contains(names, definition.Name) {
+			return nil, errors.New("pickup \"" + definition.Name + "\" is defined more than once in filename \"" + path + "\"")
+		}
+		names = append(names, definition.Name)
+	}
+
+	return file.Pickups, nil
+}
+
+func (d *PickupDefinition) validate() error {
+
+	if d.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	if _, ok := assets.SpriteMap[d.Sprite]; !ok {
+		return errors.New("sprite \"" + d.Sprite + "\" was not found in the sprite map")
+	}
+
+	if !slices.Contains(pickupStatusEffects, d.Effect.Type) && !slices.Contains(pickupEffects, d.Effect.Type) {
+		return errors.New("unknown effect \"" + d.Effect.Type + "\"")
+	}
+
+	if d.Audio != "" {
+		if !audio.Exists(d.Audio) {
+			return errors.New("audio \"" + d.Audio + "\" was not found")
+		}
+
+		if audioType := getAudioType(d.Audio); audioType != "mp3" && audioType != "wav" {
+			return errors.New("audio \"" + d.Audio + "\" must be an mp3 or wav file")
+		}
+	}
+
+	if d.Tint != nil && len(d.Tint) != 3 && len(d.Tint) != 4 {
+		return errors.New("tint must have 3 or 4 values (red, green, blue & alpha)")
+	}
+
+	for _, value := range d.Tint {
+		if value < 0 || value > 255 {
+			return errors.New("tint values must be between 0 and 255")
+		}
+	}
+
+	if (d.Weight != nil && *d.Weight < 0) || (d.AudioVolume != nil && *d.AudioVolume < 0) || d.MinWave < 0 || d.MaxConcurrent < 0 || d.Lifetime < 0 || d.Effect.Amount < 0 {
+		return errors.New("weight, audioVolume, minWave, maxConcurrent, lifetime and effect amount cannot be negative")
+	}
+
+	return nil
+}
+
+// Creates a pickup type from the definition, filling in the defaults (audio is set by the catalogue)
+func (d *PickupDefinition) build() *PickupType {
+
+	pickupType := &PickupType{
+		name:          d.Name,
+		spriteName:    d.Sprite,
+		audioName:     "pickup.wav",
+		audioVolume:   0.5,
+		weight:        1.0,
+		minWave:       d.MinWave,
+		maxConcurrent: d.MaxConcurrent,
+		lifetime:      d.Lifetime,
+		effect:        d.Effect,
+	}
+
+	if d.Audio != "" {
+		pickupType.audioName = d.Audio
+	}
+	if d.AudioVolume != nil {
+		pickupType.audioVolume = *d.AudioVolume
+	}
+	if d.Weight != nil {
+		pickupType.weight = *d.Weight
+	}
+	if len(d.Tint) >= 3 {
+		tint := color.RGBA{uint8(d.Tint[0]), uint8(d.Tint[1]), uint8(d.Tint[2]), 255}
+		if len(d.Tint) == 4 {
+			tint.A = uint8(d.Tint[3])
+		}
+		pickupType.tint = tint
+	}
+
+	return pickupType
+}
+
+func countPickups(pickups []*Pickup, pickupType *PickupType) int {
+	count := 0
+	for _, pickup := range pickups {
+		if !pickup.disabled && pickup.pickupType == pickupType {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Gets the audio type from the file extension
+func getAudioType(filename string) string {
+	return strings.TrimPrefix(filepath.Ext(filename), ".")
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
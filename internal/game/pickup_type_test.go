@@ -0,0 +1,32 @@
+package game
+
+import "testing"
+
+// Pickups which would load and then fail (or do nothing) in game are rejected when loaded
+func TestPickupValidation(t *testing.T) {
+
+	valid := PickupDefinition{Name: "test", Sprite: "pill_blue", Effect: PickupEffect{Type: "shield"}}
+
+	err := valid.validate()
+	if err != nil {
+		t.Fatalf("expected the pickup to be valid, got: %v", err)
+	}
+
+	invalid := map[string]func(d *PickupDefinition){
+		"unhandled status effect": func(d *PickupDefinition) { d.Effect.Type = "slowed" },
+		"tint above 255":          func(d *PickupDefinition) { d.Tint = []int{256, 0, 0} },
+		"negative tint":           func(d *PickupDefinition) { d.Tint = []int{0, 0, 0, -1} },
+		"missing audio":           func(d *PickupDefinition) { d.Audio = "missing.wav" },
+	}
+
+	for name, change := range invalid {
+		t.Run(name, func(t *testing.T) {
+			definition := valid
+			change(&definition)
+
+			if definition.validate() == nil {
+				t.Errorf("expected the pickup to be invalid")
+			}
+		})
+	}
+}
@@ -1,72 +1,60 @@
 package game
 
 import (
-	"go-game-space-shooter/internal/assets"
-	"go-game-space-shooter/internal/audio"
+	"go-game-space-shooter/internal/config"
 	"image/color"
 	"math"
-	"strconv"
-	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-func NewPlayer() *Player {
-	sprite, err := assets.NewSprite("player")
-	if err != nil {
-		HandleError(err)
-	}
-
-	attackAudio, err := audio.NewAudio("laser.wav", "wav")
-	if err != nil {
-		HandleError(err)
-	}
-
-	hitAudio, err := audio.NewAudio("damage2.wav", "wav")
-	if err != nil {
-		HandleError(err)
-	}
-
-	wsX, wsY := GetWindowSize()
+// Creates the player, with the sound effects of the game (none if it's headless)
+func NewPlayer(arena *Arena, sounds *Sounds) *Player {
+	wsX, wsY := arena.Size()
 
 	player := Player{
-		character: &Character{
-			position: &CharacterVector{
-				vector: &Vector{
-					x: float64(wsX) / 2.0,
-					y: float64(wsY) / 2.0,
-				},
+		Entity: &Entity{
+			transform: &Vector{
+				x:     float64(wsX) / 2.0,
+				y:     float64(wsY) / 2.0,
 				angle: 0.0,
 				scale: 0.6,
 			},
+			renderer: NewSpriteRenderer("player", SPRITE_LAYER_PLAYER),
+			collider: NewColliderComponent(color.RGBA{0, 0, 255, 255}),
 			movement: &Movement{
 				velocity:        10.0,
 				turningVelocity: 5.0,
 			},
-			sprite: sprite,
-			hp: &Health{
+			health: &Health{
 				max:     100.0,
 				current: 100.0,
 			},
-		},
-		attack: &Attack{
-			spriteName:       "laser_blue",
-			fireRate:         6.0,
-			velocity:         10.0,
-			damage:           5.0,
-			criticalChance:   5.0,
-			criticalModifier: 2.0,
-			audio:            attackAudio,
-			hitAudio:         hitAudio,
+			attack: &Attack{
+				spriteName:       "laser_blue",
+				fireRate:         6.0,
+				velocity:         10.0,
+				damage:           5.0,
+				criticalChance:   5.0,
+				criticalModifier: 2.0,
+				hitAudio:         sounds.playerHit,
+			},
 		},
 	}
 
 	// Apply configs
 	player.applyConfigs()
 
-	// Create attack timer
-	player.attack.timer = NewTimer(time.Millisecond * time.Duration(1.0/player.attack.fireRate*1000))
+	// Create status effects
+	player.effects = NewStatusEffects()
+
+	// Create weapons, starting with the first one
+	player.weapons = NewWeapons(player.attack, sounds)
+	player.attack = player.weapons[0].attack
+
+	// Apply configs: Attack Volume
+	player.setAttackVolume(Configs.AttackVolume)
 
 	return &player
 }
@@ -78,205 +66,166 @@ func (p *Player) Update(g *Game) {
 	}
 
 	if g.state == GameStatePlaying {
-		p.updateMovement()
+		p.effects.Update()
+		p.updateMovement(g)
+		p.updateWeapon(g)
 		p.updateAttack(g)
 	}
 }
 
-func (p *Player) Draw(screen *ebiten.Image) {
+// Draws the effects around the player (its sprite is drawn by the world)
+func (p *Player) Draw(screen *ebiten.Image, alpha float64) {
 
 	if p.disabled {
 		return
 	}
 
-	op := &ebiten.DrawImageOptions{}
-
-	p.character.sprite.Rotate(op, p.character.position.angle)
-	p.character.sprite.Scale(op, p.character.position.scale)
-	p.character.sprite.Translate(op, p.character.position.scale, p.character.position.vector.x, p.character.position.vector.y)
-
-	screen.DrawImage(p.character.sprite.Image, op)
-
-	op.GeoM.Reset()
-
-	// Config: Draw Colission Rects
-	if Configs["DRAW_COLLISION_RECTS"] == "1" && p.character.position.collision != nil {
-		// Draw collision rectangle
-		vector.StrokeRect(screen, float32(p.character.position.collision.x0), float32(p.character.position.collision.y0), float32(p.character.position.collision.x1-p.character.position.collision.x0), float32(p.character.position.collision.y1-p.character.position.collision.y0), 1.0, color.RGBA{0, 0, 255, 255}, true)
+	// Shield: A ring around the player, where its sprite is drawn
+	if p.effects.Has("shield") {
+		transform := p.getInterpolatedTransform(alpha)
+		radius := float64(p.renderer.sprite.Image.Bounds().Dx()) * transform.scale * 0.75
+		vector.StrokeCircle(screen, float32(transform.x), float32(transform.y), float32(radius), 2.0, statusEffectTypes["shield"].color, true)
 	}
 }
 
-func (p *Player) OffsetHp(offset float64) {
-
-	tmp := p.character.hp.current
-
-	p.character.hp.current += offset
+// Hurts the player, unless shielded, and applies the status effect of the attack (if any)
+func (p *Player) TakeDamage(g *Game, damage float64, effect string) {
 
-	if p.character.hp.current <= 0 {
-		p.character.hp.current = 0
-		p.disabled = true
-
-	} else if p.character.hp.current > p.character.hp.max {
-		p.character.hp.current = p.character.hp.max
+	if p.effects.Has("shield") {
+		return
 	}
 
-	if tmp > p.character.hp.current {
-		// ? DEBUG
-		// fmt.Println("Player hit:", tmp, "->", p.character.hp.current)
+	if g.world.Damage(g, p.Entity, damage, "hurt") && effect != "" {
+		p.effects.Apply(effect, 0)
 	}
 }
 
 func (p *Player) applyConfigs() {
 
-	configs := p.getConfigs()
-
-	// Apply configs: Attack Volume
-	if configs["attack_volume"] >= 0.00 {
-		p.attack.audio.SetVolume(configs["attack_volume"])
-	}
-
 	// Apply configs: Player Scale
-	if configs["player_scale"] > 0.00 {
-		p.character.position.scale = configs["player_scale"]
-	}
+	p.transform.scale = Configs.PlayerScale
 
 	// Apply configs: Player HP
-	if configs["player_hp"] > 0.00 {
-		p.character.hp.max = configs["player_hp"]
-		p.character.hp.current = configs["player_hp"]
-	}
+	p.health.max = Configs.PlayerHp
+	p.health.current = Configs.PlayerHp
 
 	// Apply config: Player Fire Rate
-	if configs["player_fire_rate"] > 0.00 {
-		p.attack.fireRate = configs["player_fire_rate"]
-	}
+	p.attack.fireRate = Configs.PlayerFireRate
 
 	// Apply config: Player Projectile Speed
-	if configs["player_projectile_speed"] >= 0.00 {
-		p.attack.velocity = configs["player_projectile_speed"]
-	}
+	p.attack.velocity = Configs.PlayerProjectileSpeed
 
-	// Apply config: Player Projectile Speed
-	if configs["player_projectile_damage"] > 0.00 {
-		p.attack.damage = configs["player_projectile_damage"]
-	}
+	// Apply config: Player Projectile Damage
+	p.attack.damage = Configs.PlayerProjectileDamage
 }
 
-func (p *Player) getConfigs() map[string]float64 {
-	var val float64
-	var err error
+// Applies reloaded configs to the player and its weapons, keeping the HP it has left and the power-ups it picked up
+func (p *Player) reloadConfigs(previous *config.Config) {
 
-	configs := make(map[string]float64)
+	// Apply configs: Player Scale
+	p.transform.scale = Configs.PlayerScale
 
-	// Config: Attack Volume
-	val, err = strconv.ParseFloat(Configs["ATTACK_VOLUME"], 64)
-	if err == nil {
-		configs["attack_volume"] = val
-	}
+	// Apply configs: Player HP
+	p.health.current *= Configs.PlayerHp / p.health.max
+	p.health.max = Configs.PlayerHp
 
-	// Config: Player Scale
-	val, err = strconv.ParseFloat(Configs["PLAYER_SCALE"], 64)
-	if err == nil {
-		configs["player_scale"] = val
-	}
+	// Apply configs: Attack Volume
+	p.setAttackVolume(Configs.AttackVolume)
 
-	// Config: Player HP
-	val, err = strconv.ParseFloat(Configs["PLAYER_HP"], 64)
-	if err == nil {
-		configs["player_hp"] = val
-	}
+	for _, weapon := range p.weapons {
+		// Apply config: Player Fire Rate
+		weapon.attack.SetFireRate(Configs.PlayerFireRate * weapon.weaponType.fireRate)
 
-	// Config: Player Fire Rate
-	val, err = strconv.ParseFloat(Configs["PLAYER_FIRE_RATE"], 64)
-	if err == nil {
-		configs["player_fire_rate"] = val
-	}
+		// Apply config: Player Projectile Speed
+		weapon.attack.velocity = Configs.PlayerProjectileSpeed * weapon.weaponType.velocity
 
-	// Config: Player Projectile Speed
-	val, err = strconv.ParseFloat(Configs["PLAYER_PROJECTILE_SPEED"], 64)
-	if err == nil {
-		configs["player_projectile_speed"] = val
+		// Apply config: Player Projectile Damage (damage power-ups multiply it)
+		weapon.attack.damage *= Configs.PlayerProjectileDamage / previous.PlayerProjectileDamage
 	}
+}
 
-	// Config: Player Projectile Speed
-	val, err = strconv.ParseFloat(Configs["PLAYER_PROJECTILE_DAMAGE"], 64)
-	if err == nil {
-		configs["player_projectile_damage"] = val
+// Sets the volume of every weapon's sound (headless games have none)
+func (p *Player) setAttackVolume(volume float64) {
+	for _, weapon := range p.weapons {
+		if weapon.attack.audio != nil {
+			weapon.attack.audio.SetVolume(volume)
+		}
 	}
-
-	return configs
 }
 
-func (p *Player) updateMovement() {
+func (p *Player) updateMovement(g *Game) {
+
+	// Slowed: The player moves slower
+	velocity := p.movement.velocity * p.effects.GetVelocityModifier()
 
 	// Flag to check if the player is turning
 	var turning int8 = 0
 
 	// Player Controls: Up
-	if ebiten.IsKeyPressed(ebiten.KeyW) {
-		p.character.position.vector.y -= p.character.movement.velocity
+	if g.input.up {
+		p.transform.y -= velocity
 	}
 
 	// Player Controls: Down
-	if ebiten.IsKeyPressed(ebiten.KeyS) {
-		p.character.position.vector.y += p.character.movement.velocity
+	if g.input.down {
+		p.transform.y += velocity
 	}
 
 	// Player Controls: Left
-	if ebiten.IsKeyPressed(ebiten.KeyA) {
-		p.character.position.vector.x -= p.character.movement.velocity
+	if g.input.left {
+		p.transform.x -= velocity
 		turning = -1
 	}
 
 	// Player Controls: Right
-	if ebiten.IsKeyPressed(ebiten.KeyD) {
-		p.character.position.vector.x += p.character.movement.velocity
+	if g.input.right {
+		p.transform.x += velocity
 		turning = 1
 	}
 
 	// Rotate back to the original position
 	if turning == 0 {
-		if p.character.position.angle > 0 {
+		if p.transform.angle > 0 {
 			turning = -1
-		} else if p.character.position.angle < 0 {
+		} else if p.transform.angle < 0 {
 			turning = 1
 		}
 	}
 
-	p.character.position.angle = p.getPositionAngle()
+	p.transform.angle = p.getPositionAngle(g.input.GetAimTarget(p.transform))
 
 	// Guarantee the player remains within bounds
-	p.character.position.vector.x, p.character.position.vector.y = CheckWithinBounds(
-		p.character.position.vector.x,
-		p.character.position.vector.y,
-		float64(p.character.sprite.Image.Bounds().Dx()),
-		float64(p.character.sprite.Image.Bounds().Dy()),
-		p.character.position.scale,
+	p.transform.x, p.transform.y = CheckWithinBounds(
+		g.arena,
+		p.transform.x,
+		p.transform.y,
+		float64(p.renderer.sprite.Image.Bounds().Dx()),
+		float64(p.renderer.sprite.Image.Bounds().Dy()),
+		p.transform.scale,
 	)
 
-	// Update collision rectangle
-	x0, y0, x1, y1 := GetSpriteRectCoords(p.character.position.vector, p.character.sprite, p.character.position.scale)
-	p.character.position.collision = &CollisionRect{x0: x0 - 5, y0: y0 - 25, x1: x1 + 2, y1: y1 + 5}
+	// Update collision shape
+	g.world.UpdateCollider(p.Entity)
 
 	// ? DEBUG
-	// fmt.Println(p.character.position.collision.x0, p.character.position.collision.y0, p.character.position.collision.x1, p.character.position.collision.y1, float64(float64(p.character.sprite.Image.Bounds().Dx())*p.character.position.scale))
+	// fmt.Println(p.collider.GetBounds().x0, p.collider.GetBounds().y0, p.collider.GetBounds().x1, p.collider.GetBounds().y1, float64(float64(p.renderer.sprite.Image.Bounds().Dx())*p.transform.scale))
 }
 
 func (p *Player) updateAttack(g *Game) {
 
-	p.attack.timer.Update()
+	p.attack.emitter.Update(g)
+
+	// Rapid fire: The attack timer runs faster
+	p.attack.timer.UpdateScaled(p.effects.GetFireRateModifier())
 	if p.attack.timer.IsReady() {
 
 		// Player Controls: Shoot
-		if ebiten.IsKeyPressed(ebiten.KeySpace) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		fire, damageModifier := p.GetWeapon().PullTrigger(g.input.fire)
+		if fire {
 			p.attack.timer.Reset()
 
-			// Find middle of character  position vector
-			projectileX := p.character.position.vector.x
-			projectileY := p.character.position.vector.y - ((float64(p.character.sprite.Image.Bounds().Dy())) / 2.0)
-
 			// Attack values
-			attackDamage := p.attack.damage
+			attackDamage := p.attack.damage * damageModifier * p.effects.GetDamageModifier()
 			attackCritical := false
 
 			// Calculate critical
@@ -285,20 +234,27 @@ func (p *Player) updateAttack(g *Game) {
 				attackDamage *= p.attack.criticalModifier
 			}
 
-			// Create a new projectile
-			projectile := NewProjectile("player", p.character, p.attack.spriteName, projectileX, projectileY, p.character.position.angle, p.attack.velocity, attackDamage, attackCritical, p.attack.hitAudio)
-			projectile.SetProjectileDirection(GetCursorVector())
-
-			// Add to projectile list
-			g.projectiles = append(g.projectiles, projectile)
+			// Fire the projectiles, from the middle of character position vector
+			p.attack.emitter.Fire(g, &ProjectileShot{
+				ownerTag:   "player",
+				owner:      p.Entity,
+				spriteName: p.attack.spriteName,
+				tint:       p.attack.tint,
+				offsetY:    -(float64(p.renderer.sprite.Image.Bounds().Dy())) / 2.0,
+				velocity:   p.attack.velocity,
+				damage:     attackDamage,
+				critical:   attackCritical,
+				modifiers:  p.attack.modifiers,
+				hitAudio:   p.attack.hitAudio,
+			}, g.input.GetAimTarget(p.transform))
 
 			// Play the attack audio
-			p.attack.audio.Play()
+			g.PlayAudio(p.attack.audio)
 		}
 	}
 }
 
-func (p *Player) getPositionAngle() float64 {
-	dx, dy, _ := DistanceBetweenTwoPoints(p.character.position.vector, GetCursorVector())
+func (p *Player) getPositionAngle(target *Vector) float64 {
+	dx, dy, _ := DistanceBetweenTwoPoints(p.transform, target)
 	return ((math.Atan2(dy, dx) * 180) / math.Pi) + 90
 }
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/audio"
+	"slices"
+)
+
+func NewProjectilePool() *ProjectilePool {
+	return &ProjectilePool{
+		free: nil,
+	}
+}
+
+// Gets a projectile from the pool, or creates one if the pool is empty
+func (pp *ProjectilePool) Get(ownerTag string, spriteName string, x float64, y float64, initialAngle float64, velocity float64, damage float64, critical bool, hitAudio *audio.Audio) *Projectile {
+
+	if len(pp.free) == 0 {
+		return NewProjectile(ownerTag, spriteName, x, y, initialAngle, velocity, damage, critical, hitAudio)
+	}
+
+	projectile := pp.free[len(pp.free)-1]
+	pp.free[len(pp.free)-1] = nil
+	pp.free = pp.free[:len(pp.free)-1]
+
+	projectile.reset(ownerTag, spriteName, x, y, initialAngle, velocity, damage, critical, hitAudio)
+
+	return projectile
+}
+
+// Returns a projectile to the pool, it mustn't be used afterwards
+func (pp *ProjectilePool) Put(p *Projectile) {
+
+	// Don't hold on to the enemies it has hit
+	clear(p.hits)
+	p.hits = p.hits[:0]
+	p.disabled = true
+
+	pp.free = append(pp.free, p)
+}
+
+// Removes the disabled entities (and the projectiles which left the arena), keeping the order of the others.
+// Runs on every tick, so Update & Draw only go through live entities
+func (g *Game) removeDisabledEntities() {
+
+	g.projectiles = slices.DeleteFunc(g.projectiles, func(projectile *Projectile) bool {
+		if !projectile.disabled && !projectile.IsOutOfBounds(g.arena) {
+			return false
+		}
+
+		g.projectilePool.Put(projectile)
+		return true
+	})
+
+	g.enemies = slices.DeleteFunc(g.enemies, func(enemy *Enemy) bool {
+		return enemy.disabled
+	})
+
+	g.pickups = slices.DeleteFunc(g.pickups, func(pickup *Pickup) bool {
+		return pickup.disabled
+	})
+
+	g.world.RemoveDisabled()
+}
+
+// Returns every projectile to the pool, e.g. when the game restarts
+func (g *Game) clearProjectiles() {
+
+	for _, projectile := range g.projectiles {
+		g.projectilePool.Put(projectile)
+	}
+
+	clear(g.projectiles)
+	g.projectiles = g.projectiles[:0]
+}
@@ -3,105 +3,107 @@ package game
 import (
 	"go-game-space-shooter/internal/assets"
 	"go-game-space-shooter/internal/audio"
-	"image"
 	"image/color"
 	"math"
-
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-func NewProjectile(ownerTag string, owner *Character, spriteName string, x float64, y float64, initialAngle float64, velocity float64, damage float64, critical bool, hitAudio *audio.Audio) *Projectile {
-	sprite, err := assets.NewSprite(spriteName)
-	if err != nil {
-		HandleError(err)
-	}
+// Creates a projectile, projectiles fired during the game come from the pool instead (see ProjectilePool)
+func NewProjectile(ownerTag string, spriteName string, x float64, y float64, initialAngle float64, velocity float64, damage float64, critical bool, hitAudio *audio.Audio) *Projectile {
 
 	projectile := Projectile{
-		position: &Vector{
-			x:     x,
-			y:     y,
-			angle: initialAngle,
-			scale: 1,
-		},
-		sprite: sprite,
-		movement: &Movement{
-			velocity: velocity,
+		Entity: &Entity{
+			transform: &Vector{},
+			renderer:  &SpriteRenderer{layer: SPRITE_LAYER_PROJECTILES},
+			collider:  NewColliderComponent(color.RGBA{0, 255, 0, 255}),
+			movement:  &Movement{},
+			direction: &MovementDirection{},
 		},
-		direction: &MovementDirection{
-			oDx:   0,
-			oDy:   0,
-			angle: initialAngle,
-		},
-		hitAudio: hitAudio,
-		ownerTag: ownerTag,
-		owner:    *owner,
-		damage:   damage,
-		critical: critical,
-		disabled: false,
 	}
 
+	projectile.reset(ownerTag, spriteName, x, y, initialAngle, velocity, damage, critical, hitAudio)
+
 	return &projectile
 }
 
-func (p *Projectile) Update(g *Game) {
+// Sets every field of the projectile, reusing its components & hits
+func (p *Projectile) reset(ownerTag string, spriteName string, x float64, y float64, initialAngle float64, velocity float64, damage float64, critical bool, hitAudio *audio.Audio) {
+	sprite, err := assets.NewSprite(spriteName)
+	if err != nil {
+		HandleError(err)
+	}
 
-	if p.disabled {
-		return
+	*p.transform = Vector{
+		x:     x,
+		y:     y,
+		angle: initialAngle,
+		scale: 1,
+	}
+	*p.movement = Movement{
+		velocity: velocity,
+	}
+	*p.direction = MovementDirection{
+		oDx:   0,
+		oDy:   0,
+		angle: initialAngle,
 	}
 
-	p.updateMovement()
-	p.checkCollisions(g)
+	p.renderer.sprite = sprite
+	p.renderer.tint = nil
+	p.collider.ready = false
+	p.lifetime = nil
+	p.hitAudio = hitAudio
+	p.ownerTag = ownerTag
+	p.damage = damage
+	p.critical = critical
+	p.modifiers = ProjectileModifiers{}
+	p.effect = ""
+	p.hits = p.hits[:0]
+	p.bounced = 0
+	p.disabled = false
 }
 
-func (p *Projectile) Draw(screen *ebiten.Image) {
+func (p *Projectile) Update(g *Game) {
 
 	if p.disabled {
 		return
 	}
 
-	op := &ebiten.DrawImageOptions{}
-
-	p.sprite.Rotate(op, p.position.angle)
-	p.sprite.Scale(op, p.position.scale)
-	p.sprite.Translate(op, p.position.scale, p.position.x, p.position.y)
-
-	screen.DrawImage(p.sprite.Image, op)
-
-	op.GeoM.Reset()
-
-	// Config: Draw Colission Rects
-	if Configs["DRAW_COLLISION_RECTS"] == "1" {
-		// Draw collision rectangle
-		vector.StrokeRect(screen, float32(p.collision.x0), float32(p.collision.y0), float32(p.collision.x1-p.collision.x0), float32(p.collision.y1-p.collision.y0), 1.0, color.RGBA{0, 255, 0, 255}, true)
+	// Max lifetime
+	p.updateLifetime()
+	if p.disabled {
+		return
 	}
+
+	p.updateMovement(g)
+	p.checkCollisions(g)
 }
 
 func (p *Projectile) SetProjectileDirection(target *Vector) {
-	p.direction.oDx, p.direction.oDy, _ = DistanceBetweenTwoPoints(p.position, target)
-	p.position.angle = ((math.Atan2(p.direction.oDy, p.direction.oDx) * 180) / math.Pi) - 90
+	p.direction.oDx, p.direction.oDy, _ = DistanceBetweenTwoPoints(p.transform, target)
+	p.transform.angle = ((math.Atan2(p.direction.oDy, p.direction.oDx) * 180) / math.Pi) - 90
 }
 
-func (p *Projectile) IsOutOfBounds() bool {
+func (p *Projectile) IsOutOfBounds(arena *Arena) bool {
 
-	wsX, wsY := GetWindowSize()
+	wsX, wsY := arena.Size()
 
-	Dx := p.sprite.Image.Bounds().Dx()
-	Dy := p.sprite.Image.Bounds().Dy()
+	Dx := p.renderer.sprite.Image.Bounds().Dx()
+	Dy := p.renderer.sprite.Image.Bounds().Dy()
 
-	return p.position.x < -float64(Dx) || p.position.x > wsX+float64(Dx) || p.position.y < -float64(Dy) || p.position.y > wsY+float64(Dy)
+	return p.transform.x < -float64(Dx) || p.transform.x > wsX+float64(Dx) || p.transform.y < -float64(Dy) || p.transform.y > wsY+float64(Dy)
 }
 
-func (p *Projectile) updateMovement() {
+func (p *Projectile) updateMovement(g *Game) {
+
+	p.updateHoming(g)
 
 	// Set position based on directional movement
-	p.position.x += p.direction.oDx * p.movement.velocity
-	p.position.y += p.direction.oDy * p.movement.velocity
+	g.world.Move(p.Entity)
 
-	// Update collision rectangle
-	x0, y0, x1, y1 := GetSpriteRectCoords(p.position, p.sprite, p.position.scale)
-	p.collision = &CollisionRect{x0: x0 - 20, y0: y0 + 20, x1: x1 + 20, y1: y1 + 20}
+	p.updateBouncing(g)
 
+	// Update collision shape
+	g.world.UpdateCollider(p.Entity)
 }
 
 func (p *Projectile) checkCollisions(g *Game) {
@@ -110,67 +112,66 @@ func (p *Projectile) checkCollisions(g *Game) {
 		return
 	}
 
-	srcRect := image.Rect(p.collision.x0, p.collision.y0, p.collision.x1, p.collision.y1)
-
 	// Check collisions with Player
 	if p.ownerTag == "enemy" {
-		if !g.player.disabled && srcRect.Overlaps(image.Rect(g.player.character.position.collision.x0, g.player.character.position.collision.y0, g.player.character.position.collision.x1, g.player.character.position.collision.y1)) {
+		g.grid.QueryShape(p.collider.shape, func(c *Collider) bool {
+			if c.player == nil || c.player.disabled {
+				return true
+			}
 
 			// Play the hit audio
-			p.hitAudio.Play()
+			g.PlayAudio(p.hitAudio)
 
-			// Remove from player's HP
-			g.player.OffsetHp(-p.damage)
+			// Hurt the player
+			c.player.TakeDamage(g, p.damage, p.effect)
 
 			// Disable the projectile
 			p.disabled = true
 
-			// Add to game's damage numbers
-			g.damageNumbers = append(g.damageNumbers, DamageNumber{
-				damage:      p.damage,
-				x:           float64(g.player.character.position.collision.x0 + (g.player.character.position.collision.x1-g.player.character.position.collision.x0)/2),
-				y:           float64(g.player.character.position.collision.y0),
-				effect:      "hurt",
-				ticksPassed: 0,
-			})
-		}
+			return false
+		})
 
 	} else if p.ownerTag == "player" {
 
-		for _, enemy := range g.enemies {
-
-			if !enemy.disabled && srcRect.Overlaps(image.Rect(enemy.character.position.collision.x0, enemy.character.position.collision.y0, enemy.character.position.collision.x1, enemy.character.position.collision.y1)) {
+		g.grid.QueryShape(p.collider.shape, func(c *Collider) bool {
+			enemy := c.enemy
 
-				// Play the hit audio
-				p.hitAudio.Play()
+			if enemy == nil || enemy.disabled || p.hasHit(enemy) {
+				return true
+			}
 
-				// Remove from enemy's HP
-				enemy.OffsetHp(-p.damage)
+			// Play the hit audio
+			g.PlayAudio(p.hitAudio)
 
-				// Add to game's damage numbers
-				damageNumberEffect := ""
-				if p.critical {
-					damageNumberEffect = "golden"
-				}
-				g.damageNumbers = append(g.damageNumbers, DamageNumber{
-					damage:      p.damage,
-					x:           float64(enemy.character.position.collision.x0 + (enemy.character.position.collision.x1-enemy.character.position.collision.x0)/2),
-					y:           float64(enemy.character.position.collision.y0),
-					effect:      damageNumberEffect,
-					ticksPassed: 0,
-				})
+			p.hitEnemy(g, enemy, p.damage, p.critical)
+			p.splash(g, enemy)
 
-				// Disable the projectile
+			// Disable the projectile, once it has gone through as many enemies as it can pierce
+			p.hits = append(p.hits, enemy)
+			if len(p.hits) > p.modifiers.Pierce {
 				p.disabled = true
+				return false
+			}
+
+			return true
+		})
+	}
+}
 
-				// If enemy was killed, add to score
-				if enemy.disabled {
-					// ? DEBUG
-					// fmt.Println("enemy killed, awards points:", enemy.worthPoints)
+func (p *Projectile) hitEnemy(g *Game, enemy *Enemy, damage float64, critical bool) {
 
-					g.score.AddScore(enemy.worthPoints)
-				}
-			}
-		}
+	// Remove from enemy's HP
+	damageNumberEffect := ""
+	if critical {
+		damageNumberEffect = "golden"
+	}
+	g.world.Damage(g, enemy.Entity, damage, damageNumberEffect)
+
+	// If enemy was killed, add to score
+	if enemy.disabled {
+		// ? DEBUG
+		// fmt.Println("enemy killed, awards points:", enemy.worthPoints)
+
+		g.score.AddScore(enemy.worthPoints)
 	}
 }
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"errors"
+	"image"
+	"math"
+	"slices"
+)
+
+// Splash damage, relative to the projectile's damage
+const PROJECTILE_SPLASH_DAMAGE_MODIFIER = 0.5
+
+// Combines two sets of modifiers: homing, piercing and bouncing add up, the largest splash and the shortest lifetime are kept
+func (m ProjectileModifiers) Combine(other ProjectileModifiers) ProjectileModifiers {
+
+	combined := ProjectileModifiers{
+		Homing:   m.Homing + other.Homing,
+		Pierce:   m.Pierce + other.Pierce,
+		Bounce:   m.Bounce + other.Bounce,
+		Splash:   math.Max(m.Splash, other.Splash),
+		Lifetime: m.Lifetime,
+	}
+
+	if combined.Lifetime == 0 || (other.Lifetime > 0 && other.Lifetime < combined.Lifetime) {
+		combined.Lifetime = other.Lifetime
+	}
+
+	return combined
+}
+
+func (m *ProjectileModifiers) validate() error {
+
+	if m.Homing < 0 || m.Pierce < 0 || m.Bounce < 0 || m.Splash < 0 || m.Lifetime < 0 {
+		return errors.New("projectile modifiers cannot be negative")
+	}
+
+	return nil
+}
+
+func (p *Projectile) SetModifiers(modifiers ProjectileModifiers) {
+	p.modifiers = modifiers
+
+	p.lifetime = nil
+	if modifiers.Lifetime > 0 {
+		// The timer is kept when the projectile goes back to the pool
+		if p.lifetimeTimer == nil {
+			p.lifetimeTimer = NewTimer(secondsToDuration(modifiers.Lifetime))
+		} else {
+			p.lifetimeTimer.SetDuration(secondsToDuration(modifiers.Lifetime))
+		}
+		p.lifetime = p.lifetimeTimer
+	}
+}
+
+// Homing: Turns towards the nearest valid target, up to the turn rate (in degrees per tick)
+func (p *Projectile) updateHoming(g *Game) {
+
+	if p.modifiers.Homing <= 0 {
+		return
+	}
+
+	target := p.getHomingTarget(g)
+	if target == nil {
+		return
+	}
+
+	current := math.Atan2(p.direction.oDy, p.direction.oDx)
+	desired := math.Atan2(target.y-p.transform.y, target.x-p.transform.x)
+
+	// Shortest turn, limited by the turn rate
+	turn := math.Remainder(desired-current, 2*math.Pi)
+	maxTurn := p.modifiers.Homing * math.Pi / 180.0
+	turn = math.Max(-maxTurn, math.Min(maxTurn, turn))
+
+	p.setDirectionAngle(current + turn)
+}
+
+func (p *Projectile) getHomingTarget(g *Game) *Vector {
+
+	if p.ownerTag == "enemy" {
+		if g.player.disabled {
+			return nil
+		}
+
+		return g.player.transform
+	}
+
+	var target *Vector
+	targetLength := math.Inf(1)
+
+	for _, enemy := range g.enemies {
+		if enemy.disabled || p.hasHit(enemy) {
+			continue
+		}
+
+		_, _, length := DistanceBetweenTwoPoints(p.transform, enemy.transform)
+		if length < targetLength {
+			target = enemy.transform
+			targetLength = length
+		}
+	}
+
+	return target
+}
+
+// Bouncing: Reflects off the arena edges, until it runs out of bounces
+func (p *Projectile) updateBouncing(g *Game) {
+
+	if p.bounced >= p.modifiers.Bounce {
+		return
+	}
+
+	wsX, wsY := g.arena.Size()
+	bounced := false
+
+	// The position is reflected back inside too, fast projectiles would otherwise be out of bounds (and removed)
+	if p.transform.x < 0 && p.direction.oDx < 0 {
+		p.transform.x = -p.transform.x
+		p.direction.oDx *= -1
+		bounced = true
+	} else if p.transform.x > wsX && p.direction.oDx > 0 {
+		p.transform.x = 2*wsX - p.transform.x
+		p.direction.oDx *= -1
+		bounced = true
+	}
+
+	if p.transform.y < 0 && p.direction.oDy < 0 {
+		p.transform.y = -p.transform.y
+		p.direction.oDy *= -1
+		bounced = true
+	} else if p.transform.y > wsY && p.direction.oDy > 0 {
+		p.transform.y = 2*wsY - p.transform.y
+		p.direction.oDy *= -1
+		bounced = true
+	}
+
+	if bounced {
+		p.bounced++
+		p.setDirectionAngle(math.Atan2(p.direction.oDy, p.direction.oDx))
+	}
+}
+
+// Splash: Damages the other enemies around the impact
+func (p *Projectile) splash(g *Game, hit *Enemy) {
+
+	if p.modifiers.Splash <= 0 {
+		return
+	}
+
+	radius := int(math.Ceil(p.modifiers.Splash))
+	area := image.Rect(int(p.transform.x)-radius, int(p.transform.y)-radius, int(p.transform.x)+radius, int(p.transform.y)+radius)
+
+	g.grid.Query(area, func(c *Collider) bool {
+		enemy := c.enemy
+
+		if enemy == nil || enemy == hit || enemy.disabled {
+			return true
+		}
+
+		_, _, length := DistanceBetweenTwoPoints(p.transform, enemy.transform)
+		if length <= p.modifiers.Splash {
+			p.hitEnemy(g, enemy, p.damage*PROJECTILE_SPLASH_DAMAGE_MODIFIER, false)
+		}
+
+		return true
+	})
+}
+
+// Piercing: Checks if the projectile has already gone through an enemy, so it doesn't hit it twice
+func (p *Projectile) hasHit(enemy *Enemy) bool {
+	return slices.Contains(p.hits, enemy)
+}
+
+func (p *Projectile) setDirectionAngle(angle float64) {
+	p.direction.oDx = math.Cos(angle)
+	p.direction.oDy = math.Sin(angle)
+	p.transform.angle = ((angle * 180) / math.Pi) - 90
+}
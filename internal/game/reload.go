@@ -0,0 +1,131 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/config"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// How often the configs file is checked for changes
+const CONFIG_RELOAD_POLL_INTERVAL = time.Second
+
+// How long the reload is shown on screen
+const CONFIG_RELOAD_MESSAGE_DURATION = 2 * time.Second
+
+// Key to reload the configs, even if the file didn't change
+const CONFIG_RELOAD_KEY = ebiten.KeyF5
+
+func NewConfigReloader(source *config.Source, flags map[string]string) *ConfigReloader {
+	return &ConfigReloader{
+		source:    source,
+		flags:     flags,
+		modTime:   source.GetModTime(),
+		lastCheck: time.Now(),
+	}
+}
+
+// Reloads the configs whenever their file changes, or the reload key is pressed.
+// The window, TPS, seed, save file and data files still need a restart
+func (g *Game) WatchConfigs(source *config.Source, flags map[string]string) {
+	g.configReloader = NewConfigReloader(source, flags)
+}
+
+// Checks if the configs need to be reloaded, on every update (in real time, not in simulation ticks)
+func (r *ConfigReloader) Update(g *Game) {
+
+	reload := inpututil.IsKeyJustPressed(CONFIG_RELOAD_KEY)
+
+	if time.Since(r.lastCheck) >= CONFIG_RELOAD_POLL_INTERVAL {
+		r.lastCheck = time.Now()
+
+		modTime := r.source.GetModTime()
+		if !modTime.Equal(r.modTime) {
+			r.modTime = modTime
+			reload = true
+		}
+	}
+
+	if reload {
+		r.Reload(g)
+	}
+}
+
+// Loads the configs again and applies them to the game. Invalid configs are kept on screen, and the game keeps the previous ones
+func (r *ConfigReloader) Reload(g *Game) {
+
+	configs, err := config.Load(r.source, r.flags)
+	if err != nil {
+		r.err = err
+		return
+	}
+
+	r.err = nil
+	r.reloadedAt = time.Now()
+
+	previous := Configs
+	Configs = configs
+
+	g.applyConfigs(previous)
+}
+
+// Gets the message to show on screen, and if it's an error (empty if there's nothing to show)
+func (r *ConfigReloader) GetMessage() (string, bool) {
+
+	if r.err != nil {
+		return "Configs not reloaded: " + r.err.Error(), true
+	}
+
+	if !r.reloadedAt.IsZero() && time.Since(r.reloadedAt) < CONFIG_RELOAD_MESSAGE_DURATION {
+		return "Configs reloaded", false
+	}
+
+	return "", false
+}
+
+// Applies the configs to the running game, its timers and live entities
+func (g *Game) applyConfigs(previous *config.Config) {
+
+	// Config: Time Scale
+	g.clock.SetTimeScale(Configs.TimeScale)
+
+	// Config: Maximum Enemies per Wave
+	max_enemies_per_wave = Configs.MaxEnemiesPerWave
+
+	// Config: Enemy Spawn Time & Pickup Spawn Time (keeping how far along they are)
+	g.enemySpawnTimer.Resize(time.Duration(Configs.EnemySpawnTime) * time.Second)
+	g.pickupSpawnTimer.Resize(time.Duration(Configs.PickupSpawnTime) * time.Second)
+
+	// Config: Music Volume
+	if g.music != nil {
+		g.music.SetVolume(Configs.MusicVolume)
+	}
+
+	// Player configs
+	g.player.reloadConfigs(previous)
+
+	// Enemy types are built from the enemy configs, and live enemies take on their new type
+	g.enemyTypes = NewEnemyRegistry(g.data.Enemies)
+	for _, enemy := range g.enemies {
+		enemy.setArchetype(g.enemyTypes.Get(enemy.enemyType))
+	}
+
+	// A replay can't reproduce a run whose configs changed midway, so it's no longer recorded (the next run is)
+	if g.state == GameStateInitial {
+		g.startReplay()
+	} else {
+		g.replay = nil
+	}
+}
+
+// Changes the fire rate of an attack, and rebuilds its timer if it changed
+func (a *Attack) SetFireRate(fireRate float64) {
+
+	if a.fireRate == fireRate && a.timer != nil {
+		return
+	}
+
+	a.fireRate = fireRate
+	a.timer = NewTimer(time.Millisecond * time.Duration(1.0/fireRate*1000))
+}
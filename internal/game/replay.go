@@ -0,0 +1,270 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"go-game-space-shooter/internal/config"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	REPLAY_VERSION        = 1
+	REPLAY_FILE_EXTENSION = ".replay"
+	REPLAY_FOLDER         = "replays"
+)
+
+// Input keys, packed as a bitmask in each replay tick
+const (
+	REPLAY_KEY_UP uint8 = 1 << iota
+	REPLAY_KEY_DOWN
+	REPLAY_KEY_LEFT
+	REPLAY_KEY_RIGHT
+	REPLAY_KEY_FIRE
+	REPLAY_KEY_AIM // aiming with the right stick, its direction is recorded instead of the cursor
+)
+
+func NewReplay(seed int64, configs map[string]string, data *GameData, arena *Arena) *Replay {
+	return &Replay{
+		Version:  REPLAY_VERSION,
+		Seed:     seed,
+		Configs:  maps.Clone(configs),
+		Width:    arena.width,
+		Height:   arena.height,
+		GameData: *data,
+		Ticks:    []ReplayTick{},
+	}
+}
+
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{}
+
+	err = json.Unmarshal(data, replay)
+	if err != nil {
+		return nil, errors.New("cannot decode replay with filename \"" + path + "\"")
+	}
+
+	if replay.Version != REPLAY_VERSION {
+		return nil, errors.New("replay version " + strconv.Itoa(replay.Version) + " is not supported (expected " + strconv.Itoa(REPLAY_VERSION) + ")")
+	}
+
+	if replay.Width <= 0 || replay.Height <= 0 {
+		return nil, errors.New("replay with filename \"" + path + "\" has an invalid arena size")
+	}
+
+	err = replay.GameData.Validate()
+	if err != nil {
+		return nil, errors.New("replay with filename \"" + path + "\" has invalid game data: " + err.Error())
+	}
+
+	return replay, nil
+}
+
+func (r *Replay) Write(path string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0660)
+}
+
+// Records the input and arena size of a single tick
+func (r *Replay) Record(input *Input, arena *Arena) {
+
+	cursor := input.cursor
+	if input.aim != nil {
+		cursor = input.aim
+	}
+
+	tick := ReplayTick{
+		Keys:    encodeReplayKeys(input),
+		CursorX: cursor.x,
+		CursorY: cursor.y,
+		Width:   arena.width,
+		Height:  arena.height,
+		Weapon:  input.weapon,
+		Scroll:  input.scroll,
+	}
+
+	// Repeated ticks are merged together, to keep the replay file small
+	if len(r.Ticks) > 0 {
+		last := &r.Ticks[len(r.Ticks)-1]
+
+		previous := *last
+		previous.Count = 0
+		if previous == tick {
+			last.Count++
+			return
+		}
+	}
+
+	tick.Count = 1
+	r.Ticks = append(r.Ticks, tick)
+}
+
+// Stores the outcome of the run, so playback can be verified against it
+func (r *Replay) Finish(g *Game) {
+	r.Score = g.score.GetScore()
+	r.Wave = g.currentWave
+}
+
+// Gets the configs from the snapshot, with the seed of the recorded run (configs added since the replay was recorded keep their default)
+func (r *Replay) GetConfigs() (*config.Config, error) {
+	configs, err := config.FromMap(r.Configs)
+	if err != nil {
+		return nil, errors.New("replay configs: " + err.Error())
+	}
+
+	configs.GameSeed = r.Seed
+
+	return configs, nil
+}
+
+// Plays the replay back without a window, and checks it reaches the recorded score and wave
+func (r *Replay) Verify() error {
+
+	configs, err := r.GetConfigs()
+	if err != nil {
+		return err
+	}
+
+	g := NewHeadlessGame(configs, &r.GameData, r.Width, r.Height)
+	playback := NewReplayPlayback(r)
+
+	for g.state == GameStatePlaying {
+		input := playback.Next(g.arena)
+		if input == nil {
+			break
+		}
+
+		g.Tick(input)
+	}
+
+	if g.score.GetScore() != r.Score {
+		return errors.New("replay ended with score " + strconv.FormatInt(g.score.GetScore(), 10) + ", expected " + strconv.FormatInt(r.Score, 10))
+	}
+
+	if g.currentWave != r.Wave {
+		return errors.New("replay ended on wave " + strconv.Itoa(g.currentWave) + ", expected " + strconv.Itoa(r.Wave))
+	}
+
+	return nil
+}
+
+func NewReplayPlayback(replay *Replay) *ReplayPlayback {
+	return &ReplayPlayback{
+		replay: replay,
+		index:  0,
+		count:  0,
+	}
+}
+
+// Gets the input of the next recorded tick and applies its arena size, or nil once the replay is over
+func (p *ReplayPlayback) Next(arena *Arena) *Input {
+
+	if p.index >= len(p.replay.Ticks) {
+		return nil
+	}
+
+	tick := p.replay.Ticks[p.index]
+
+	p.count++
+	if p.count >= tick.Count {
+		p.index++
+		p.count = 0
+	}
+
+	arena.width = tick.Width
+	arena.height = tick.Height
+
+	return decodeReplayKeys(tick.Keys, tick.Weapon, tick.Scroll, tick.CursorX, tick.CursorY)
+}
+
+func (p *ReplayPlayback) Rewind() {
+	p.index = 0
+	p.count = 0
+}
+
+// Starts recording a new run, if enabled in the configs
+func (g *Game) startReplay() {
+	g.replay = nil
+
+	// Config: Record Replays
+	if g.headless || g.playback != nil || !Configs.RecordReplays {
+		return
+	}
+
+	g.replay = NewReplay(g.seed, Configs.ToMap(), g.data, g.arena)
+}
+
+// Writes the recorded run to the replays folder, next to the save file
+func (g *Game) saveReplay() error {
+	if g.replay == nil {
+		return nil
+	}
+
+	g.replay.Finish(g)
+
+	filename := time.Now().Format("2006-01-02_15-04-05") + "_" + strconv.FormatInt(g.seed, 10) + REPLAY_FILE_EXTENSION
+
+	return g.replay.Write(filepath.Join(g.save.GetDirectory(), REPLAY_FOLDER, filename))
+}
+
+func encodeReplayKeys(input *Input) uint8 {
+	var keys uint8
+
+	if input.up {
+		keys |= REPLAY_KEY_UP
+	}
+	if input.down {
+		keys |= REPLAY_KEY_DOWN
+	}
+	if input.left {
+		keys |= REPLAY_KEY_LEFT
+	}
+	if input.right {
+		keys |= REPLAY_KEY_RIGHT
+	}
+	if input.fire {
+		keys |= REPLAY_KEY_FIRE
+	}
+	if input.aim != nil {
+		keys |= REPLAY_KEY_AIM
+	}
+
+	return keys
+}
+
+func decodeReplayKeys(keys uint8, weapon int, scroll int, cursorX float64, cursorY float64) *Input {
+	input := NewInput(
+		keys&REPLAY_KEY_UP != 0,
+		keys&REPLAY_KEY_DOWN != 0,
+		keys&REPLAY_KEY_LEFT != 0,
+		keys&REPLAY_KEY_RIGHT != 0,
+		keys&REPLAY_KEY_FIRE != 0,
+		weapon,
+		scroll,
+		cursorX,
+		cursorY,
+	)
+
+	if keys&REPLAY_KEY_AIM != 0 {
+		input.aim = &Vector{x: cursorX, y: cursorY}
+	}
+
+	return input
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Re-records the replays in testdata, once the gameplay changed on purpose: go test ./internal/game -run Replay -update
+var update = flag.Bool("update", false, "re-record the replays in testdata")
+
+// Records a headless run of the scripted input, the cursor moving a few times per second to keep the replay small
+func recordTestReplay(seed int64, d time.Duration) *Replay {
+
+	g := newTestGame(seed)
+	replay := NewReplay(g.seed, Configs.ToMap(), g.data, g.arena)
+
+	for tick := range int(d / GetTickDuration()) {
+		if g.state != GameStatePlaying {
+			break
+		}
+
+		input := getTestInput(tick - tick%15)
+
+		replay.Record(input, g.arena)
+		g.Tick(input)
+	}
+
+	replay.Finish(g)
+
+	return replay
+}
+
+// Recording a run and playing it back reaches the same score and wave, also once written & loaded
+func TestReplayRoundTrip(t *testing.T) {
+
+	replay := recordTestReplay(TEST_SEED, time.Minute)
+
+	err := replay.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "run"+REPLAY_FILE_EXTENSION)
+
+	err = replay.Write(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = loaded.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The recorded replays still play out the same, so gameplay changes which break them are caught
+func TestReplayFixtures(t *testing.T) {
+
+	if *update {
+		err := recordTestReplay(TEST_SEED, time.Minute).Write(filepath.Join("testdata", "run"+REPLAY_FILE_EXTENSION))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*"+REPLAY_FILE_EXTENSION))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatal("no replays in testdata")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			replay, err := LoadReplay(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = replay.Verify()
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Playing a replay back keeps the high score, and leaves the save file alone once the run ends
+func TestReplayPlaybackKeepsHighScore(t *testing.T) {
+
+	replay := recordTestReplay(TEST_SEED, time.Minute)
+	if replay.Score == 0 {
+		t.Fatal("expected the replay to score points")
+	}
+
+	configs, err := replay.GetConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewHeadlessGame(configs, &replay.GameData, replay.Width, replay.Height)
+	g.save = &Save{path: t.TempDir(), filename: "test.save", data: make(map[string]any)}
+	g.score.SetHighScore(1)
+
+	// As a replay game starts
+	g.playback = NewReplayPlayback(replay)
+	g.Restart()
+
+	for input := g.playback.Next(g.arena); input != nil; input = g.playback.Next(g.arena) {
+		g.Tick(input)
+	}
+
+	// As a windowed game does once the run ends
+	g.saveOnDeath()
+
+	if g.score.GetScore() != replay.Score {
+		t.Errorf("expected the replay to score %d, got %d", replay.Score, g.score.GetScore())
+	}
+	if g.score.GetHighScore() != 1 {
+		t.Errorf("expected the high score to stay at 1, got %d", g.score.GetHighScore())
+	}
+
+	_, err = os.Stat(filepath.Join(g.save.GetDirectory(), g.save.filename))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the save file to be left alone, got: %v", err)
+	}
+}
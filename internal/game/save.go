@@ -1,7 +1,7 @@
 package game
 
 import (
-	"errors"
+	"go-game-space-shooter/internal/config"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -11,7 +11,8 @@ import (
 	"github.com/joho/godotenv"
 )
 
-const SAVE_FILE_FOLDER = "go-game-space-shooter"
+// The configs file can be stored in the same folder
+const SAVE_FILE_FOLDER = config.USER_CONFIG_FOLDER
 
 func NewSave() *Save {
 	userConfigDir, err := os.UserConfigDir()
@@ -21,17 +22,10 @@ func NewSave() *Save {
 
 	data := make(map[string]any)
 
-	saveFileName := Configs["SAVE_FILE_NAME"]
-	if saveFileName == "" {
-		HandleError(errors.New("save file name cannot be empty"))
-	}
-	if filepath.Ext(saveFileName) != ".save" {
-		HandleError(errors.New("save file extension must be \".save\""))
-	}
-
+	// Config: Save File Name (validated with the configs)
 	return &Save{
 		path:     userConfigDir,
-		filename: saveFileName,
+		filename: Configs.SaveFileName,
 		data:     data,
 	}
 }
@@ -50,6 +44,9 @@ func (s *Save) Save(game *Game) (bool, error) {
 	// Save: Highscore
 	data["HIGHSCORE"] = strconv.FormatInt(game.score.GetHighScore(), 10)
 
+	// Save: Leaderboard
+	game.leaderboard.Store(data)
+
 	if !maps.Equal(data, origData) {
 		godotenv.Write(data, filepath.Join(s.path, SAVE_FILE_FOLDER, s.filename))
 	}
@@ -72,11 +69,19 @@ func (s *Save) LoadSave(game *Game, return_only bool) (data map[string]string) {
 		if err == nil {
 			game.score.SetHighScore(highscore)
 		}
+
+		// Load: Leaderboard
+		game.leaderboard.Load(data)
 	}
 
 	return data
 }
 
+// Gets the folder where the save file (and other user data) is stored
+func (s *Save) GetDirectory() string {
+	return filepath.Join(s.path, SAVE_FILE_FOLDER)
+}
+
 func (s *Save) createFileIfNotExists() error {
 	err := os.Mkdir(filepath.Join(s.path, SAVE_FILE_FOLDER), 0750)
 	if err != nil && !os.IsExist(err) {
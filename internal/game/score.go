@@ -27,10 +27,15 @@ func (s *Score) SetHighScore(highscore int64) {
 	s.best = highscore
 }
 
+// Keeps the high score as it is, whatever the score reaches (e.g. while a replay is played back)
+func (s *Score) KeepHighScore(keep bool) {
+	s.keepsBest = keep
+}
+
 func (s *Score) AddScore(add int64) {
 	s.current += add
 
-	if s.IsHighScore() {
+	if s.IsHighScore() && !s.keepsBest {
 		s.best = s.current
 	}
 }
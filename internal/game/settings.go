@@ -0,0 +1,303 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/widget"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	SETTINGS_ROW_SPACING         = 12
+	SETTINGS_BINDING_ROW_SPACING = 4
+	SETTINGS_BINDING_FONT_SIZE   = 20
+	SETTINGS_BINDING_WIDTH       = 160
+	SETTINGS_TITLE_SIZE          = 60
+	SETTINGS_TITLE_SPACING       = 40
+	SETTINGS_COLUMN_WIDTH        = 320
+	SETTINGS_COLUMN_GAP          = 20
+	SETTINGS_SLIDER_WIDTH        = 240
+	SETTINGS_VOLUME_STEP         = 0.05
+)
+
+// Window sizes to choose from in the settings
+var settingWindowSizes = [][2]int{{960, 540}, {1280, 720}, {1600, 900}, {1920, 1080}}
+
+// Creates the settings screen, each row has its label on the left and its value on the right
+func (u *Ui) newSettingsMenu() *widget.Panel {
+
+	w := &u.settingWidgets
+
+	w.musicVolume = widget.NewSlider(Configs.MusicVolume, SETTINGS_VOLUME_STEP, SETTINGS_SLIDER_WIDTH, func(value float64) {
+		// Config: Music Volume
+		Configs.MusicVolume = value
+		u.game.music.SetVolume(value)
+	})
+
+	w.sfxVolume = widget.NewSlider(Configs.SfxVolume, SETTINGS_VOLUME_STEP, SETTINGS_SLIDER_WIDTH, func(value float64) {
+		// Config: SFX Volume (applied as each sound effect plays)
+		Configs.SfxVolume = value
+	})
+
+	w.fullscreen = widget.NewToggle(Configs.FullscreenEnabled, func(value bool) {
+		// Config: Fullscreen Enabled
+		Configs.FullscreenEnabled = value
+		ebiten.SetFullscreen(value)
+	})
+
+	w.windowSize = widget.NewButton("", u.nextWindowSize)
+
+	w.drawCollisionRects = widget.NewToggle(Configs.DrawCollisionRects, func(value bool) {
+		// Config: Draw Collision Rects
+		Configs.DrawCollisionRects = value
+	})
+
+	w.drawDamageNumbers = widget.NewToggle(Configs.DrawDamageNumbers, func(value bool) {
+		// Config: Draw Damage Numbers
+		Configs.DrawDamageNumbers = value
+	})
+
+	rows := widget.NewList(SETTINGS_ROW_SPACING,
+		u.newSettingRow("Music Volume", w.musicVolume),
+		u.newSettingRow("SFX Volume", w.sfxVolume),
+		u.newSettingRow("Fullscreen", w.fullscreen),
+		u.newSettingRow("Window Size", w.windowSize),
+		u.newSettingRow("Draw Collision Shapes", w.drawCollisionRects),
+		u.newSettingRow("Damage Numbers", w.drawDamageNumbers),
+		widget.NewButton("Key Bindings", u.openKeyBindings),
+		widget.NewButton("Back", u.goBack),
+	)
+
+	return u.newSettingsPanel("Settings", rows)
+}
+
+// Creates the key bindings screen, a row for each action with a column for each binding
+func (u *Ui) newKeyBindingsMenu() *widget.Panel {
+
+	rows := widget.NewList(SETTINGS_BINDING_ROW_SPACING)
+
+	for action := range ActionCount {
+		label := u.newSettingLabel(GetActionLabel(action))
+		label.Size = SETTINGS_BINDING_FONT_SIZE
+
+		row := widget.NewRow(SETTINGS_COLUMN_GAP*2, label)
+
+		for slot := range ACTION_MAP_MAX_BINDINGS {
+			button := widget.NewButton("", func() {
+				u.rebinding = true
+				u.rebindingAction = action
+				u.rebindingSlot = slot
+			})
+			button.Size = SETTINGS_BINDING_FONT_SIZE
+			button.Align = widget.AlignStart
+			button.MinWidth = SETTINGS_BINDING_WIDTH
+
+			u.settingWidgets.bindings[action][slot] = button
+			row.Children = append(row.Children, button)
+		}
+
+		rows.Children = append(rows.Children, row)
+	}
+
+	rows.Children = append(rows.Children,
+		widget.NewButton("Reset to Defaults", func() {
+			u.game.actions.ResetToDefaults()
+		}),
+		widget.NewButton("Back", u.goBack),
+	)
+
+	return u.newSettingsPanel("Key Bindings", rows)
+}
+
+// A settings screen: the title above the rows, on top of the menus
+func (u *Ui) newSettingsPanel(title string, rows *widget.List) *widget.Panel {
+
+	label := widget.NewLabel(title)
+	label.Size = SETTINGS_TITLE_SIZE
+	label.Color = &u.theme.AccentColor
+
+	panel := widget.NewPanel(widget.NewList(SETTINGS_TITLE_SPACING, label, rows))
+	panel.Background = color.RGBA{0, 0, 0, uint8(math.Floor(255 * 0.75))}
+
+	return panel
+}
+
+// A row with the label on the left of the setting, both as wide so the gap between them is centered
+func (u *Ui) newSettingRow(label string, setting widget.Widget) *widget.List {
+
+	switch s := setting.(type) {
+	case *widget.Slider:
+		s.MinWidth = SETTINGS_COLUMN_WIDTH
+	case *widget.Toggle:
+		s.MinWidth = SETTINGS_COLUMN_WIDTH
+	case *widget.Button:
+		s.MinWidth = SETTINGS_COLUMN_WIDTH
+		s.Align = widget.AlignStart
+	}
+
+	return widget.NewRow(SETTINGS_COLUMN_GAP*2, u.newSettingLabel(label), setting)
+}
+
+func (u *Ui) newSettingLabel(str string) *widget.Label {
+	label := widget.NewLabel(str)
+	label.Align = widget.AlignEnd
+	label.MinWidth = SETTINGS_COLUMN_WIDTH
+	label.Color = &u.theme.LabelColor
+
+	return label
+}
+
+func (u *Ui) openSettings() {
+	u.screen = UiScreenSettings
+	u.settingsErr = nil
+	u.settingsBefore = Configs.GetSettings()
+	u.settingsMenu.ClearFocus()
+}
+
+func (u *Ui) openKeyBindings() {
+	u.screen = UiScreenKeyBindings
+	u.keyBindingsMenu.ClearFocus()
+}
+
+// Saves the settings changed since the screen was opened and closes it, it stays open if they can't be saved
+func (u *Ui) closeSettings() {
+
+	err := Configs.SaveSettings(u.settingsBefore)
+	if err != nil {
+		u.settingsErr = err
+		return
+	}
+
+	u.settingsErr = nil
+	u.screen = UiScreenNone
+}
+
+func (u *Ui) updateSettings(input *widget.Input) {
+
+	// Key Bindings: The next key or mouse button pressed is bound
+	if u.rebinding {
+		u.updateRebinding()
+		return
+	}
+
+	// Go back
+	if u.game.actions.IsJustPressed(ActionBack) {
+		u.goBack()
+		return
+	}
+
+	u.refreshSettings()
+	u.updateMenu(u.getSettingsMenu(), input)
+}
+
+func (u *Ui) goBack() {
+	switch u.screen {
+	case UiScreenKeyBindings:
+		// Save the key bindings, it stays open if they can't be saved
+		err := u.game.actions.Save(u.game.GetActionMapPath())
+		if err != nil {
+			u.settingsErr = err
+			return
+		}
+
+		// The bindings file was replaced by valid bindings
+		u.settingsErr = nil
+		u.game.bindingsErr = nil
+		u.screen = UiScreenSettings
+	case UiScreenSettings:
+		u.closeSettings()
+	}
+}
+
+func (u *Ui) getSettingsMenu() *widget.Panel {
+	if u.screen == UiScreenKeyBindings {
+		return u.keyBindingsMenu
+	}
+
+	return u.settingsMenu
+}
+
+// Shows the current values of the settings & key bindings
+func (u *Ui) refreshSettings() {
+
+	w := &u.settingWidgets
+
+	w.musicVolume.Value = Configs.MusicVolume
+	w.sfxVolume.Value = Configs.SfxVolume
+	w.fullscreen.Value = Configs.FullscreenEnabled
+	w.windowSize.Text = strconv.Itoa(Configs.WindowWidth) + "x" + strconv.Itoa(Configs.WindowHeight)
+	w.drawCollisionRects.Value = Configs.DrawCollisionRects
+	w.drawDamageNumbers.Value = Configs.DrawDamageNumbers
+
+	for action := range ActionCount {
+		for slot, button := range w.bindings[action] {
+			button.Active = u.rebinding && action == u.rebindingAction && slot == u.rebindingSlot
+			button.Text = "-"
+
+			if button.Active {
+				button.Text = "press a key..."
+			} else if binding, ok := u.game.actions.GetBinding(action, slot); ok {
+				button.Text = binding.String()
+			}
+		}
+	}
+}
+
+// Binds the next key or mouse button pressed to the action being rebound. Escape cancels, and Backspace clears the binding
+func (u *Ui) updateRebinding() {
+
+	binding, ok := ReadJustPressedBinding()
+	if !ok {
+		return
+	}
+
+	switch {
+	case !binding.isMouse && binding.key == ebiten.KeyEscape:
+		// Cancel
+
+	case !binding.isMouse && binding.key == ebiten.KeyBackspace:
+		u.game.actions.ClearBinding(u.rebindingAction, u.rebindingSlot)
+
+	default:
+		u.game.actions.SetBinding(u.rebindingAction, u.rebindingSlot, binding)
+	}
+
+	u.rebinding = false
+}
+
+// Config: Window Width & Height, moves to the next size after the current one
+func (u *Ui) nextWindowSize() {
+
+	next := 0
+	for i, size := range settingWindowSizes {
+		if size[0] == Configs.WindowWidth && size[1] == Configs.WindowHeight {
+			next = (i + 1) % len(settingWindowSizes)
+		}
+	}
+
+	Configs.WindowWidth = settingWindowSizes[next][0]
+	Configs.WindowHeight = settingWindowSizes[next][1]
+	ebiten.SetWindowSize(Configs.WindowWidth, Configs.WindowHeight)
+}
+
+func (u *Ui) drawSettings(screen *ebiten.Image) {
+	wsX, wsY := GetWindowSize()
+
+	menu := u.getSettingsMenu()
+	u.refreshSettings()
+	u.layoutMenu(menu)
+	menu.Draw(screen, u.theme)
+
+	// Settings which couldn't be saved
+	if u.settingsErr != nil {
+		op := &text.DrawOptions{}
+		u.font.Size = 24
+		op.ColorScale.Scale(255/255.0, 80/255.0, 80/255.0, 255/255.0)
+		op.PrimaryAlign = text.AlignCenter
+		op.GeoM.Translate(wsX/2.0, wsY-WINDOW_PADDING-SETTINGS_TITLE_SPACING)
+		text.Draw(screen, "Settings not saved: "+u.settingsErr.Error(), u.font, op)
+	}
+}
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/assets"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Moves the collision shape of a sprite (or creates it, if nil) to where the sprite is drawn, with the same scale and rotation (in degrees).
+// The shape is updated in place, so it doesn't allocate on every tick
+func SetSpriteShape(shape *CollisionShape, position *Vector, sprite *assets.Sprite, scale float64, angle float64) *CollisionShape {
+
+	if shape == nil {
+		shape = &CollisionShape{}
+	}
+
+	if scale == 0.0 {
+		scale = 1.0
+	}
+
+	x, y := sprite.GetCenter(scale, position.x, position.y)
+	shape.Set(sprite.GetHitbox(), x, y, scale, angle)
+
+	return shape
+}
+
+// Creates a collision shape from a hitbox, centered on x & y, scaled and rotated (in degrees) around its center
+func NewCollisionShape(hitbox *assets.Hitbox, x float64, y float64, scale float64, angle float64) *CollisionShape {
+	shape := &CollisionShape{}
+	shape.Set(hitbox, x, y, scale, angle)
+
+	return shape
+}
+
+// Sets the shape from a hitbox (checked by assets.ValidateHitboxes when the game starts), reusing its points
+func (s *CollisionShape) Set(hitbox *assets.Hitbox, x float64, y float64, scale float64, angle float64) {
+
+	sin, cos := math.Sincos(angle * math.Pi / 180.0)
+
+	// Transforms a point of the hitbox to the screen, the same way the sprite is drawn
+	transform := func(px float64, py float64) Vector {
+		px *= scale
+		py *= scale
+		return Vector{x: x + px*cos - py*sin, y: y + px*sin + py*cos}
+	}
+
+	s.circle = false
+	s.points = s.points[:0]
+
+	switch hitbox.Shape {
+	case "circle":
+		s.circle = true
+		s.center = transform(hitbox.OffsetX, hitbox.OffsetY)
+		s.radius = hitbox.Radius * scale
+
+	case "box":
+		w := hitbox.Width / 2
+		h := hitbox.Height / 2
+		s.points = append(s.points,
+			transform(hitbox.OffsetX-w, hitbox.OffsetY-h),
+			transform(hitbox.OffsetX+w, hitbox.OffsetY-h),
+			transform(hitbox.OffsetX+w, hitbox.OffsetY+h),
+			transform(hitbox.OffsetX-w, hitbox.OffsetY+h),
+		)
+
+	case "polygon":
+		for _, point := range hitbox.Points {
+			s.points = append(s.points, transform(hitbox.OffsetX+point[0], hitbox.OffsetY+point[1]))
+		}
+	}
+
+	s.bounds = s.getBounds()
+}
+
+// Gets the bounding box of the shape, used by the broad phase
+func (s *CollisionShape) GetBounds() *CollisionRect {
+	return &s.bounds
+}
+
+// Checks if two shapes overlap, with the separating axis theorem
+func (s *CollisionShape) Overlaps(other *CollisionShape) bool {
+
+	if s == nil || other == nil {
+		return false
+	}
+
+	// Cheap rejection first
+	if !s.bounds.Rect().Overlaps(other.bounds.Rect()) {
+		return false
+	}
+
+	if s.circle && other.circle {
+		radius := s.radius + other.radius
+		dx := s.center.x - other.center.x
+		dy := s.center.y - other.center.y
+		return dx*dx+dy*dy <= radius*radius
+	}
+
+	if s.circle {
+		return other.overlapsCircle(s)
+	}
+	if other.circle {
+		return s.overlapsCircle(other)
+	}
+
+	return !s.hasSeparatingEdge(other) && !other.hasSeparatingEdge(s)
+}
+
+func (s *CollisionShape) Draw(screen *ebiten.Image, clr color.Color) {
+
+	if s.circle {
+		vector.StrokeCircle(screen, float32(s.center.x), float32(s.center.y), float32(s.radius), 1.0, clr, true)
+		return
+	}
+
+	for i, point := range s.points {
+		next := s.points[(i+1)%len(s.points)]
+		vector.StrokeLine(screen, float32(point.x), float32(point.y), float32(next.x), float32(next.y), 1.0, clr, true)
+	}
+}
+
+// Checks if a polygon overlaps a circle
+func (s *CollisionShape) overlapsCircle(circle *CollisionShape) bool {
+
+	if s.hasSeparatingEdge(circle) {
+		return false
+	}
+
+	// The last axis to check goes from the circle to the closest corner of the polygon
+	closest := s.points[0]
+	closestLength := math.Inf(1)
+
+	for _, point := range s.points {
+		dx := point.x - circle.center.x
+		dy := point.y - circle.center.y
+
+		if length := dx*dx + dy*dy; length < closestLength {
+			closest = point
+			closestLength = length
+		}
+	}
+
+	if closestLength == 0 {
+		return true
+	}
+
+	return !isSeparatingAxis(s, circle, closest.x-circle.center.x, closest.y-circle.center.y)
+}
+
+// Checks if any of the polygon's edge normals separates it from the other shape
+func (s *CollisionShape) hasSeparatingEdge(other *CollisionShape) bool {
+
+	for i, point := range s.points {
+		next := s.points[(i+1)%len(s.points)]
+
+		if isSeparatingAxis(s, other, -(next.y - point.y), next.x-point.x) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gets the interval covered by the shape, projected on a normalized axis
+func (s *CollisionShape) project(ax float64, ay float64) (float64, float64) {
+
+	if s.circle {
+		center := s.center.x*ax + s.center.y*ay
+		return center - s.radius, center + s.radius
+	}
+
+	low := math.Inf(1)
+	high := math.Inf(-1)
+
+	for _, point := range s.points {
+		value := point.x*ax + point.y*ay
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+
+	return low, high
+}
+
+func (s *CollisionShape) getBounds() CollisionRect {
+
+	if s.circle {
+		return CollisionRect{
+			x0: int(math.Floor(s.center.x - s.radius)),
+			y0: int(math.Floor(s.center.y - s.radius)),
+			x1: int(math.Ceil(s.center.x + s.radius)),
+			y1: int(math.Ceil(s.center.y + s.radius)),
+		}
+	}
+
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+
+	for _, point := range s.points {
+		x0 = math.Min(x0, point.x)
+		y0 = math.Min(y0, point.y)
+		x1 = math.Max(x1, point.x)
+		y1 = math.Max(y1, point.y)
+	}
+
+	return CollisionRect{x0: int(math.Floor(x0)), y0: int(math.Floor(y0)), x1: int(math.Ceil(x1)), y1: int(math.Ceil(y1))}
+}
+
+// Checks if the projections of two shapes on an axis don't overlap
+func isSeparatingAxis(a *CollisionShape, b *CollisionShape, ax float64, ay float64) bool {
+
+	length := math.Hypot(ax, ay)
+	if length == 0 {
+		return false
+	}
+	ax /= length
+	ay /= length
+
+	aLow, aHigh := a.project(ax, ay)
+	bLow, bHigh := b.project(ax, ay)
+
+	return aHigh < bLow || bHigh < aLow
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"image"
+	"math"
+	"slices"
+)
+
+// Size of the spatial grid cells, in pixels (about the size of the largest sprites)
+const SPATIAL_GRID_CELL_SIZE = 128.0
+
+func NewSpatialGrid(cellSize float64) *SpatialGrid {
+	return &SpatialGrid{
+		cellSize: cellSize,
+	}
+}
+
+// Rebuilds the grid with the current positions of the player, enemies and pickups.
+// Cells and colliders are reused between rebuilds, to avoid allocating on every tick
+func (s *SpatialGrid) Rebuild(g *Game) {
+
+	wsX, wsY := g.arena.Size()
+
+	columns := max(1, int(math.Ceil(wsX/s.cellSize)))
+	rows := max(1, int(math.Ceil(wsY/s.cellSize)))
+
+	if columns*rows != len(s.cells) {
+		s.cells = make([][]int, columns*rows)
+	} else {
+		for i := range s.cells {
+			s.cells[i] = s.cells[i][:0]
+		}
+	}
+
+	s.columns = columns
+	s.rows = rows
+	s.colliders = s.colliders[:0]
+
+	if !g.player.disabled && g.player.collider.ready {
+		s.insert(Collider{rect: g.player.collider.GetBounds().Rect(), shape: g.player.collider.shape, player: g.player})
+	}
+
+	for _, enemy := range g.enemies {
+		if !enemy.disabled && enemy.collider.ready {
+			s.insert(Collider{rect: enemy.collider.GetBounds().Rect(), shape: enemy.collider.shape, enemy: enemy})
+		}
+	}
+
+	for _, pickup := range g.pickups {
+		if !pickup.disabled {
+			s.insert(Collider{rect: pickup.collider.GetBounds().Rect(), shape: pickup.collider.shape, pickup: pickup})
+		}
+	}
+
+	s.stamps = s.stamps[:0]
+	for range s.colliders {
+		s.stamps = append(s.stamps, 0)
+	}
+	s.query = 0
+}
+
+// Calls fn once for every collider overlapping the rectangle, in insertion order, until it returns false.
+// The matches are collected before fn is called, so it can query the grid again
+func (s *SpatialGrid) Query(rect image.Rectangle, fn func(c *Collider) bool) {
+
+	if len(s.cells) == 0 {
+		return
+	}
+
+	// Stamp the colliders as they're visited, as larger ones span more than one cell
+	s.query++
+
+	var buffer [32]int
+	matches := buffer[:0]
+
+	x0, y0, x1, y1 := s.getCellRange(rect)
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			for _, index := range s.cells[y*s.columns+x] {
+				if s.stamps[index] == s.query {
+					continue
+				}
+				s.stamps[index] = s.query
+
+				if s.colliders[index].rect.Overlaps(rect) {
+					matches = append(matches, index)
+				}
+			}
+		}
+	}
+
+	// Same order as a plain loop over the entities
+	slices.Sort(matches)
+
+	for _, index := range matches {
+		if !fn(&s.colliders[index]) {
+			return
+		}
+	}
+}
+
+// Same as Query, but only calls fn for the colliders whose shape overlaps the shape
+func (s *SpatialGrid) QueryShape(shape *CollisionShape, fn func(c *Collider) bool) {
+	s.Query(shape.GetBounds().Rect(), func(c *Collider) bool {
+		if !shape.Overlaps(c.shape) {
+			return true
+		}
+
+		return fn(c)
+	})
+}
+
+func (s *SpatialGrid) insert(collider Collider) {
+
+	index := len(s.colliders)
+	s.colliders = append(s.colliders, collider)
+
+	x0, y0, x1, y1 := s.getCellRange(collider.rect)
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			s.cells[y*s.columns+x] = append(s.cells[y*s.columns+x], index)
+		}
+	}
+}
+
+// Gets the range of cells covered by the rectangle, clamped to the grid (anything outside the arena goes in the edge cells)
+func (s *SpatialGrid) getCellRange(rect image.Rectangle) (int, int, int, int) {
+
+	clamp := func(value int, limit int) int {
+		return max(0, min(limit-1, value))
+	}
+
+	x0 := clamp(int(math.Floor(float64(rect.Min.X)/s.cellSize)), s.columns)
+	y0 := clamp(int(math.Floor(float64(rect.Min.Y)/s.cellSize)), s.rows)
+	x1 := clamp(int(math.Floor(float64(rect.Max.X)/s.cellSize)), s.columns)
+	y1 := clamp(int(math.Floor(float64(rect.Max.Y)/s.cellSize)), s.rows)
+
+	return x0, y0, x1, y1
+}
+
+func (c *CollisionRect) Rect() image.Rectangle {
+	return image.Rect(c.x0, c.y0, c.x1, c.y1)
+}
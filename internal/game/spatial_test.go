@@ -0,0 +1,91 @@
+package game
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// Fills a headless game with entities spread over the arena (half enemies, half player projectiles), at the same places for the same count
+func newCollisionTestGame(count int) *Game {
+
+	g := newTestGame(TEST_SEED)
+	random := rand.New(rand.NewSource(TEST_SEED))
+
+	for range count / 2 {
+		g.addEnemy(NewEnemy(g.enemyTypes.Get("basic"), random.Float64()*TEST_ARENA_WIDTH, random.Float64()*TEST_ARENA_HEIGHT, 0))
+	}
+
+	for range count - count/2 {
+		g.addProjectile(g.projectilePool.Get("player", g.player.attack.spriteName, random.Float64()*TEST_ARENA_WIDTH, random.Float64()*TEST_ARENA_HEIGHT, 0, 0, 1, false, nil))
+	}
+
+	return g
+}
+
+// Counts the enemies hit by the projectiles through the grid, rebuilding it first (as every tick does)
+func countGridCollisions(g *Game) int {
+
+	g.grid.Rebuild(g)
+
+	count := 0
+	for _, projectile := range g.projectiles {
+		g.grid.QueryShape(projectile.collider.shape, func(c *Collider) bool {
+			if c.enemy != nil {
+				count++
+			}
+			return true
+		})
+	}
+
+	return count
+}
+
+// Counts the enemies hit by the projectiles by checking every projectile against every enemy
+func countPairwiseCollisions(g *Game) int {
+
+	count := 0
+	for _, projectile := range g.projectiles {
+		bounds := projectile.collider.GetBounds().Rect()
+
+		for _, enemy := range g.enemies {
+			if bounds.Overlaps(enemy.collider.GetBounds().Rect()) && projectile.collider.shape.Overlaps(enemy.collider.shape) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// The grid finds the same collisions as checking every pair
+func TestSpatialGridCollisions(t *testing.T) {
+
+	g := newCollisionTestGame(500)
+
+	grid := countGridCollisions(g)
+	pairwise := countPairwiseCollisions(g)
+
+	if grid != pairwise {
+		t.Errorf("the grid found %d collisions, checking every pair found %d", grid, pairwise)
+	}
+}
+
+func BenchmarkCollisions(b *testing.B) {
+
+	for _, count := range []int{500, 1000} {
+		g := newCollisionTestGame(count)
+
+		b.Run("grid/"+strconv.Itoa(count), func(b *testing.B) {
+			for range b.N {
+				countGridCollisions(g)
+			}
+		})
+
+		b.Run("pairwise/"+strconv.Itoa(count), func(b *testing.B) {
+			for range b.N {
+				countPairwiseCollisions(g)
+			}
+		})
+	}
+}
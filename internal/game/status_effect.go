@@ -0,0 +1,209 @@
+package game
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// Stacking rules, for when an effect is applied while it's still active
+const (
+	STATUS_EFFECT_STACKING_REFRESH = "refresh" // Restarts the duration
+	STATUS_EFFECT_STACKING_EXTEND  = "extend"  // Adds to the remaining duration
+	STATUS_EFFECT_STACKING_STACK   = "stack"   // Adds a stack, up to the max stacks, and restarts the duration
+)
+
+const (
+	STATUS_EFFECT_RAPID_FIRE_MODIFIER    = 0.5 // Extra fire rate, per stack
+	STATUS_EFFECT_DOUBLE_DAMAGE_MODIFIER = 2.0
+	STATUS_EFFECT_SLOW_TIME_MODIFIER     = 0.5 // Speed of enemies and their projectiles
+	STATUS_EFFECT_SLOWED_MODIFIER        = 0.5 // Speed of the player
+)
+
+var statusEffectTypes = map[string]*StatusEffectType{
+	"rapid_fire": {
+		name:      "rapid_fire",
+		label:     "RF",
+		duration:  8.0,
+		stacking:  STATUS_EFFECT_STACKING_STACK,
+		maxStacks: 3,
+		color:     color.RGBA{255, 200, 0, 255},
+	},
+	"shield": {
+		name:      "shield",
+		label:     "SH",
+		duration:  5.0,
+		stacking:  STATUS_EFFECT_STACKING_REFRESH,
+		maxStacks: 1,
+		color:     color.RGBA{10, 191, 245, 255},
+	},
+	"double_damage": {
+		name:      "double_damage",
+		label:     "2X",
+		duration:  10.0,
+		stacking:  STATUS_EFFECT_STACKING_REFRESH,
+		maxStacks: 1,
+		color:     color.RGBA{255, 80, 80, 255},
+	},
+	"slow_time": {
+		name:      "slow_time",
+		label:     "ST",
+		duration:  4.0,
+		stacking:  STATUS_EFFECT_STACKING_EXTEND,
+		maxStacks: 1,
+		color:     color.RGBA{180, 120, 255, 255},
+	},
+	"slowed": {
+		name:      "slowed",
+		label:     "SL",
+		duration:  2.0,
+		stacking:  STATUS_EFFECT_STACKING_REFRESH,
+		maxStacks: 1,
+		debuff:    true,
+		color:     color.RGBA{120, 120, 120, 255},
+	},
+}
+
+func NewStatusEffects() *StatusEffects {
+	return &StatusEffects{
+		effects:   nil,
+		enemyTime: 0,
+	}
+}
+
+// Applies an effect for its duration (or the given duration, in seconds, if greater than 0), following its stacking rule
+func (s *StatusEffects) Apply(name string, duration float64) {
+
+	effectType, ok := statusEffectTypes[name]
+	if !ok {
+		HandleError(errors.New("status effect \"" + name + "\" does not exist"))
+	}
+
+	if duration <= 0 {
+		duration = effectType.duration
+	}
+
+	effect := s.get(name)
+	if effect == nil {
+		s.effects = append(s.effects, &StatusEffect{
+			effectType: effectType,
+			timer:      NewTimer(secondsToDuration(duration)),
+			stacks:     1,
+		})
+		return
+	}
+
+	switch effectType.stacking {
+	case STATUS_EFFECT_STACKING_EXTEND:
+		effect.timer.Extend(secondsToDuration(duration))
+
+	case STATUS_EFFECT_STACKING_STACK:
+		effect.stacks = min(effect.stacks+1, effectType.maxStacks)
+		effect.timer = NewTimer(secondsToDuration(duration))
+
+	default:
+		effect.timer = NewTimer(secondsToDuration(duration))
+	}
+}
+
+// Counts down the active effects, and removes the expired ones
+func (s *StatusEffects) Update() {
+
+	if len(s.effects) > 0 {
+		var tmp []*StatusEffect
+
+		for _, effect := range s.effects {
+			effect.timer.Update()
+
+			if !effect.timer.IsReady() {
+				tmp = append(tmp, effect)
+			}
+		}
+
+		s.effects = tmp
+	}
+}
+
+func (s *StatusEffects) Has(name string) bool {
+	return s.get(name) != nil
+}
+
+func (s *StatusEffects) GetStacks(name string) int {
+	effect := s.get(name)
+	if effect == nil {
+		return 0
+	}
+
+	return effect.stacks
+}
+
+// Gets the active effects, in the order they were applied
+func (s *StatusEffects) GetEffects() []*StatusEffect {
+	return slices.Clone(s.effects)
+}
+
+func (s *StatusEffects) GetFireRateModifier() float64 {
+	return 1.0 + float64(s.GetStacks("rapid_fire"))*STATUS_EFFECT_RAPID_FIRE_MODIFIER
+}
+
+func (s *StatusEffects) GetDamageModifier() float64 {
+	if s.Has("double_damage") {
+		return STATUS_EFFECT_DOUBLE_DAMAGE_MODIFIER
+	}
+
+	return 1.0
+}
+
+func (s *StatusEffects) GetVelocityModifier() float64 {
+	if s.Has("slowed") {
+		return STATUS_EFFECT_SLOWED_MODIFIER
+	}
+
+	return 1.0
+}
+
+// Slow-time: Gets how many ticks enemies and their projectiles are updated for on this tick
+func (s *StatusEffects) GetEnemyTicks() int {
+
+	scale := 1.0
+	if s.Has("slow_time") {
+		scale = STATUS_EFFECT_SLOW_TIME_MODIFIER
+	}
+
+	s.enemyTime += scale
+	ticks := math.Floor(s.enemyTime)
+	s.enemyTime -= ticks
+
+	return int(ticks)
+}
+
+func (s *StatusEffects) get(name string) *StatusEffect {
+	for _, effect := range s.effects {
+		if effect.effectType.name == name {
+			return effect
+		}
+	}
+
+	return nil
+}
+
+func (e *StatusEffect) GetLabel() string {
+	return e.effectType.label
+}
+
+func (e *StatusEffect) GetColor() color.RGBA {
+	return e.effectType.color
+}
+
+func (e *StatusEffect) GetStacks() int {
+	return e.stacks
+}
+
+func (e *StatusEffect) GetRemainingSeconds() float64 {
+	return e.timer.GetRemainingSeconds()
+}
+
+func (e *StatusEffect) IsDebuff() bool {
+	return e.effectType.debuff
+}
@@ -3,15 +3,21 @@ package game
 import (
 	"go-game-space-shooter/internal/assets"
 	"go-game-space-shooter/internal/audio"
+	"go-game-space-shooter/internal/config"
+	"go-game-space-shooter/internal/widget"
+	"image"
+	"image/color"
 	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
 type Score struct {
-	best    int64
-	current int64
+	best      int64
+	current   int64
+	keepsBest bool // replays played back don't beat the high score
 }
 
 type Background struct {
@@ -22,30 +28,66 @@ type Background struct {
 	oDy      float64
 }
 
-type ButtonState int
+// Screens drawn on top of the menus
+type UiScreen int
 
 const (
-	ButtonStateDefault ButtonState = iota
-	ButtonStateHover   ButtonState = iota
+	UiScreenNone        UiScreen = iota
+	UiScreenSettings    UiScreen = iota
+	UiScreenKeyBindings UiScreen = iota
+	UiScreenLeaderboard UiScreen = iota
 )
 
-type Button struct {
-	text      string
-	tag       string
-	position  *Vector
-	collision *CollisionRect
-	state     ButtonState
+// The settings widgets whose values can change elsewhere (e.g. on a configs reload), so they're refreshed on every update
+type SettingWidgets struct {
+	musicVolume        *widget.Slider
+	sfxVolume          *widget.Slider
+	fullscreen         *widget.Toggle
+	windowSize         *widget.Button
+	drawCollisionRects *widget.Toggle
+	drawDamageNumbers  *widget.Toggle
+	bindings           [ActionCount][ACTION_MAP_MAX_BINDINGS]*widget.Button
 }
 
 type Ui struct {
-	game              *Game
-	background        *Background
-	mainMenuButtons   []Button
-	pausedMenuButtons []Button
-	deathMenuButtons  []Button
-	forceCursorShape  ebiten.CursorShapeType
-	font              *text.GoTextFace
-	fontBytes         []byte
+	game             *Game
+	background       *Background
+	theme            *widget.Theme
+	mainMenu         *widget.Panel
+	pauseMenu        *widget.Panel
+	deathMenu        *widget.Panel
+	settingsMenu     *widget.Panel
+	keyBindingsMenu  *widget.Panel
+	leaderboardMenu  *widget.Panel // built when it's opened, from the current entries
+	settingWidgets   SettingWidgets
+	menuState        GameState // the game state of the last update, the focus is cleared when the menu changes
+	screen           UiScreen
+	rebinding        bool // waiting for a key or mouse button to bind
+	rebindingAction  Action
+	rebindingSlot    int
+	settingsErr      error             // shown until the settings are saved
+	settingsBefore   map[string]string // the settings when the screen was opened, only the changed ones are saved
+	naming           bool              // entering the name of the run which just placed on the leaderboard
+	name             string
+	forceCursorShape ebiten.CursorShapeType
+	font             *text.GoTextFace
+	fontBytes        []byte
+}
+
+// A run on the leaderboard
+type LeaderboardEntry struct {
+	name     string
+	score    int64
+	wave     int
+	duration time.Duration // in simulation time
+	seed     int64
+	date     time.Time
+}
+
+// The best runs, from the highest score down
+type Leaderboard struct {
+	entries []LeaderboardEntry
+	name    string // the name of the last run ranked, to fill in the next one
 }
 
 type Save struct {
@@ -71,18 +113,247 @@ type DamageNumber struct {
 	ticksPassed int
 }
 
+type Arena struct {
+	width  float64
+	height float64
+}
+
+// Runs the simulation at a fixed rate, whatever the rate of ebiten updates
+type Clock struct {
+	accumulator time.Duration // Time not simulated yet
+	timeScale   float64
+	delta       time.Duration // Time of the last update
+	lastUpdate  time.Time
+}
+
+// Reloads the configs when their file changes (or on a hotkey), and applies them to the running game
+type ConfigReloader struct {
+	source     *config.Source
+	flags      map[string]string // command-line flags, applied on top of the file
+	modTime    time.Time
+	lastCheck  time.Time
+	reloadedAt time.Time // shows the reload on screen for a while
+	err        error     // shown on screen, until the configs are valid again
+}
+
+// Something the player can do, bound to keys or mouse buttons
+type Action int
+
+type ActionInfo struct {
+	name     string   // in the bindings file
+	label    string   // shown to the player
+	defaults []string // binding names
+}
+
+// A key or a mouse button
+type Binding struct {
+	key     ebiten.Key
+	mouse   ebiten.MouseButton
+	isMouse bool
+}
+
+// The bindings of every action, each action can have a few
+type ActionMap struct {
+	bindings [ActionCount][]Binding
+	gamepads *Gamepads
+}
+
+// Every connected gamepad with the standard layout, read as a single one
+type Gamepads struct {
+	ids              []ebiten.GamepadID
+	aim              *Vector // the direction of the right stick, nil while the mouse aims
+	cursor           Vector  // the cursor on the last update, to know when the mouse moves
+	cursorMoved      bool
+	justDisconnected bool
+}
+
+type Input struct {
+	up     bool
+	down   bool
+	left   bool
+	right  bool
+	fire   bool
+	weapon int
+	scroll int
+	cursor *Vector
+	aim    *Vector // the direction of the right stick, nil while the mouse aims
+}
+
+type WaveGroup struct {
+	Enemy    string  `json:"enemy"`
+	Count    int     `json:"count"`
+	Edge     string  `json:"edge"`
+	Delay    float64 `json:"delay"`
+	Interval float64 `json:"interval"`
+	Boss     bool    `json:"boss"`
+}
+
+type WaveDefinition struct {
+	Groups []WaveGroup `json:"groups"`
+}
+
+type Campaign struct {
+	Waves []WaveDefinition `json:"waves"`
+}
+
+type BossAttack struct {
+	Pattern   string  `json:"pattern"`
+	Telegraph float64 `json:"telegraph"`
+	Duration  float64 `json:"duration"`
+	Interval  float64 `json:"interval"`
+	Cooldown  float64 `json:"cooldown"`
+	Count     int     `json:"count"`
+	Step      float64 `json:"step"`
+	Speed     float64 `json:"speed"`
+	Damage    float64 `json:"damage"`
+	Minion    string  `json:"minion"`
+}
+
+type BossPhase struct {
+	Threshold float64      `json:"threshold"`
+	Attacks   []BossAttack `json:"attacks"`
+}
+
+type BossState int
+
+const (
+	BossStateCooldown  BossState = iota
+	BossStateTelegraph BossState = iota
+	BossStateAttacking BossState = iota
+)
+
+type BossEncounter struct {
+	phases        []BossPhase
+	phase         int
+	attack        int
+	state         BossState
+	timer         *Timer
+	intervalTimer *Timer
+	emitter       *Emitter
+	angle         float64
+}
+
+type EnemyDefinition struct {
+	Name             string               `json:"name"`
+	Base             string               `json:"base"`
+	Sprite           string               `json:"sprite"`
+	ProjectileSprite string               `json:"projectileSprite"`
+	Behaviour        string               `json:"behaviour"`
+	Scale            *float64             `json:"scale"`
+	Hp               *float64             `json:"hp"`
+	Speed            *float64             `json:"speed"`
+	FireRate         *float64             `json:"fireRate"`
+	ProjectileSpeed  *float64             `json:"projectileSpeed"`
+	ProjectileDamage *float64             `json:"projectileDamage"`
+	Points           *int64               `json:"points"`
+	MinDistance      *float64             `json:"minDistance"`
+	Boss             *bool                `json:"boss"`
+	Phases           []BossPhase          `json:"phases"`
+	Emitter          *EmitterPattern      `json:"emitter"`
+	Modifiers        *ProjectileModifiers `json:"modifiers"`
+	Effect           string               `json:"effect"`
+}
+
+type EnemyArchetype struct {
+	name                 string
+	spriteName           string
+	projectileSpriteName string
+	behaviour            string
+	scale                float64
+	hp                   float64
+	velocity             float64
+	fireRate             float64
+	projectileVelocity   float64
+	damage               float64
+	worthPoints          int64
+	minLengthFromPlayer  float64
+	isBoss               bool
+	phases               []BossPhase
+	emitter              *EmitterPattern
+	modifiers            ProjectileModifiers
+	effect               string
+}
+
+type EnemyRegistry struct {
+	archetypes map[string]*EnemyArchetype
+	names      []string
+}
+
+type GameData struct {
+	Campaign *Campaign          `json:"campaign,omitempty"`
+	Enemies  []EnemyDefinition  `json:"enemies,omitempty"`
+	Pickups  []PickupDefinition `json:"pickups,omitempty"`
+}
+
+type PendingSpawn struct {
+	group *WaveGroup
+	timer *Timer
+}
+
+type ReplayTick struct {
+	Count   int     `json:"n"`
+	Keys    uint8   `json:"k"`
+	CursorX float64 `json:"x"`
+	CursorY float64 `json:"y"`
+	Width   float64 `json:"w"`
+	Height  float64 `json:"h"`
+	Weapon  int     `json:"ws,omitempty"`
+	Scroll  int     `json:"s,omitempty"`
+}
+
+type Replay struct {
+	Version int               `json:"version"`
+	Seed    int64             `json:"seed"`
+	Configs map[string]string `json:"configs"`
+	Width   float64           `json:"width"`
+	Height  float64           `json:"height"`
+	GameData
+	Score int64        `json:"score"`
+	Wave  int          `json:"wave"`
+	Ticks []ReplayTick `json:"ticks"`
+}
+
+type ReplayPlayback struct {
+	replay *Replay
+	index  int
+	count  int
+}
+
 type Game struct {
 	// Utils
 	random *rand.Rand
+	seed   int64
 	music  *audio.Audio
+	sounds *Sounds
 	save   *Save
 	state  GameState
+	arena  *Arena
+	input  *Input
+
+	// Weapon switches read on updates without a tick, applied by the next tick
+	pendingWeapon int
+	pendingScroll int
+
+	// Replays
+	replay   *Replay
+	playback *ReplayPlayback
 
 	// Mechanics
 	score            *Score
+	leaderboard      *Leaderboard
 	ui               *Ui
+	data             *GameData
+	enemyTypes       *EnemyRegistry
+	pickupTypes      *PickupCatalogue
+	grid             *SpatialGrid
+	world            *World
+	clock            *Clock
+	actions          *ActionMap
+	configReloader   *ConfigReloader
+	projectilePool   *ProjectilePool
 	enemySpawnTimer  *Timer
 	pickupSpawnTimer *Timer
+	pendingSpawns    []PendingSpawn
 	damageNumbers    []DamageNumber
 
 	// Entities
@@ -93,17 +364,68 @@ type Game struct {
 
 	// Flags
 	hasSavedOnDeath bool
+	headless        bool
 
 	// Counters
 	currentWave int
+	runTicks    int // ticks played in this run
+	placement   int // where the last run placed on the leaderboard (starting at 1), 0 if it didn't
 
 	// Misc.
 	oneSecondTimer *Timer
+	bindingsErr    error // why the bindings file couldn't be loaded, the defaults being used instead
+}
+
+// Sound effects shared by the entities, decoded once (all nil while headless)
+type Sounds struct {
+	attacks   map[string]*audio.Audio // the weapons', by file name
+	playerHit *audio.Audio
+	enemyHit  *audio.Audio
+}
+
+type Collider struct {
+	rect   image.Rectangle
+	shape  *CollisionShape
+	player *Player
+	enemy  *Enemy
+	pickup *Pickup
+}
+
+type SpatialGrid struct {
+	cellSize  float64
+	columns   int
+	rows      int
+	cells     [][]int
+	colliders []Collider
+	stamps    []int
+	query     int
 }
 
 type Timer struct {
 	currentTicks int
 	targetTicks  int
+	remainder    float64
+}
+
+type StatusEffectType struct {
+	name      string
+	label     string
+	duration  float64
+	stacking  string
+	maxStacks int
+	debuff    bool
+	color     color.RGBA
+}
+
+type StatusEffect struct {
+	effectType *StatusEffectType
+	timer      *Timer
+	stacks     int
+}
+
+type StatusEffects struct {
+	effects   []*StatusEffect
+	enemyTime float64
 }
 
 type Vector struct {
@@ -120,6 +442,15 @@ type CollisionRect struct {
 	y1 int
 }
 
+// Circle or convex polygon (boxes are 4-point polygons), in screen coordinates
+type CollisionShape struct {
+	circle bool
+	center Vector
+	radius float64
+	points []Vector
+	bounds CollisionRect
+}
+
 type Movement struct {
 	velocity        float64
 	turningVelocity float64
@@ -136,69 +467,217 @@ type Health struct {
 	current float64
 }
 
-type CharacterVector struct {
-	vector    *Vector
-	collision *CollisionRect
-	angle     float64
-	scale     float64
+// Draws the sprite of an entity
+type SpriteRenderer struct {
+	sprite *assets.Sprite
+	layer  int         // Lower layers are drawn first
+	tint   color.Color // Optional
+	hidden bool
 }
 
-type Character struct {
-	position *CharacterVector
-	movement *Movement
-	sprite   *assets.Sprite
-	hp       *Health
+// Collision shape of an entity, following its transform & sprite
+type ColliderComponent struct {
+	shape *CollisionShape
+	color color.RGBA // Used when drawing the collision shapes
+	ready bool       // Set once the shape has been placed
+}
+
+// Updates an entity with an AI, once per tick
+type AI interface {
+	Update(g *Game)
+}
+
+// An object of the world, made of components (nil when the entity doesn't have one).
+// Entity kinds (player, enemies, projectiles & pickups) embed it, and add their own behaviour
+type Entity struct {
+	id        int
+	transform *Vector // Position, angle (in degrees) & scale
+	previous  Vector  // Transform on the previous tick, to interpolate between ticks when drawing
+	renderer  *SpriteRenderer
+	collider  *ColliderComponent
+	movement  *Movement
+	direction *MovementDirection // Moves the entity on every tick, with the movement velocity
+	health    *Health
+	attack    *Attack
+	ai        AI
+	lifetime  *Timer // Disables the entity once it's over
+	disabled  bool
+}
+
+// Every entity of the game, in the order they were spawned
+type World struct {
+	entities []*Entity
+	nextId   int
 }
 
 type Attack struct {
 	spriteName       string
+	tint             color.Color // Optional
 	fireRate         float64
 	velocity         float64
 	damage           float64
 	criticalChance   float64
 	criticalModifier float64
+	modifiers        ProjectileModifiers
+	effect           string
 	timer            *Timer
+	emitter          *Emitter
 	audio            *audio.Audio
 	hitAudio         *audio.Audio
 }
 
+type EmitterPattern struct {
+	Pattern   string  `json:"pattern"`
+	Count     int     `json:"count"`
+	Step      float64 `json:"step"`
+	SpeedFrom float64 `json:"speedFrom"`
+	SpeedTo   float64 `json:"speedTo"`
+	Delay     float64 `json:"delay"`
+}
+
+type ProjectileShot struct {
+	ownerTag   string
+	owner      *Entity
+	spriteName string
+	tint       color.Color // Optional
+	offsetX    float64
+	offsetY    float64
+	velocity   float64
+	damage     float64
+	critical   bool
+	modifiers  ProjectileModifiers
+	effect     string
+	hitAudio   *audio.Audio
+}
+
+type PendingShot struct {
+	shot     ProjectileShot
+	angle    float64
+	velocity float64
+	target   *Vector
+	timer    Timer
+}
+
+type Emitter struct {
+	pattern  *EmitterPattern
+	rotation float64
+	pending  []PendingShot
+}
+
+type WeaponType struct {
+	name       string
+	spriteName string
+	tint       color.Color // Optional, weapons sharing a sprite are told apart by their tint
+	audioName  string
+	fireRate   float64
+	velocity   float64
+	damage     float64
+	chargeTime float64
+	unlockWave int
+	emitter    EmitterPattern
+	modifiers  ProjectileModifiers
+}
+
+type Weapon struct {
+	weaponType *WeaponType
+	attack     *Attack
+	charge     *Timer
+	charging   bool
+	unlocked   bool
+}
+
 type Player struct {
-	character *Character
-	attack    *Attack
-	disabled  bool
+	*Entity
+	weapons    []*Weapon
+	weaponSlot int
+	effects    *StatusEffects
+}
+
+type EnemyBehaviour interface {
+	// Moves the enemy for a single tick, and gets the direction it's facing
+	Move(e *Enemy, g *Game) (dx float64, dy float64)
+	// Checks if the enemy is able to shoot
+	CanAttack(e *Enemy) bool
 }
 
 type Enemy struct {
-	character           *Character
-	attack              *Attack
+	*Entity
+	archetype           *EnemyArchetype
+	behaviour           EnemyBehaviour
+	encounter           *BossEncounter
 	enemyType           string
+	isBoss              bool
 	worthPoints         int64
 	minLengthFromPlayer float64
 	isRunningAway       bool
 	isStopped           bool
-	disabled            bool
 }
 
 type Projectile struct {
-	position  *Vector
-	collision *CollisionRect
-	sprite    *assets.Sprite
-	movement  *Movement
-	direction *MovementDirection
+	*Entity
 	hitAudio  *audio.Audio
 	ownerTag  string
-	owner     Character
 	damage    float64
 	critical  bool
-	disabled  bool
+	modifiers ProjectileModifiers
+	effect    string
+	hits      []*Enemy
+	bounced   int
+
+	lifetimeTimer *Timer // Reused as the lifetime, once the projectile comes back from the pool
+}
+
+// Projectiles which are no longer used, ready to be fired again
+type ProjectilePool struct {
+	free []*Projectile
+}
+
+type ProjectileModifiers struct {
+	Homing   float64 `json:"homing"`
+	Pierce   int     `json:"pierce"`
+	Bounce   int     `json:"bounce"`
+	Splash   float64 `json:"splash"`
+	Lifetime float64 `json:"lifetime"`
+}
+
+type PickupEffect struct {
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+}
+
+type PickupDefinition struct {
+	Name          string       `json:"name"`
+	Sprite        string       `json:"sprite"`
+	Tint          []int        `json:"tint"`
+	Audio         string       `json:"audio"`
+	AudioVolume   *float64     `json:"audioVolume"`
+	Weight        *float64     `json:"weight"`
+	MinWave       int          `json:"minWave"`
+	MaxConcurrent int          `json:"maxConcurrent"`
+	Lifetime      float64      `json:"lifetime"`
+	Effect        PickupEffect `json:"effect"`
+}
+
+type PickupType struct {
+	name          string
+	spriteName    string
+	tint          color.Color
+	audioName     string
+	audioVolume   float64
+	audio         *audio.Audio
+	weight        float64
+	minWave       int
+	maxConcurrent int
+	lifetime      float64
+	effect        PickupEffect
+}
+
+type PickupCatalogue struct {
+	types map[string]*PickupType
+	names []string
 }
 
 type Pickup struct {
-	position     *Vector
-	collision    *CollisionRect
-	sprite       *assets.Sprite
-	audio        *audio.Audio
-	effectName   string
-	effectAmount float64
-	disabled     bool
+	*Entity
+	pickupType *PickupType
 }
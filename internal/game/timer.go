@@ -2,14 +2,13 @@ package game
 
 import (
 	"time"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// Creates a timer, counting simulation ticks (see Clock) so it doesn't depend on ebiten's TPS
 func NewTimer(d time.Duration) *Timer {
 	return &Timer{
 		currentTicks: 0,
-		targetTicks:  int(d.Milliseconds()) * ebiten.TPS() / 1000,
+		targetTicks:  int(d.Milliseconds()) * SIMULATION_TPS / 1000,
 	}
 }
 
@@ -23,10 +22,51 @@ func (t *Timer) Update() {
 	}
 }
 
+// Advances the timer by a fraction (or multiple) of a tick, carrying over the remainder
+func (t *Timer) UpdateScaled(scale float64) {
+	t.remainder += scale
+
+	for t.remainder >= 1 {
+		t.remainder--
+		t.Update()
+	}
+}
+
+// Changes the duration of the timer, and restarts it
+func (t *Timer) SetDuration(d time.Duration) {
+	t.currentTicks = 0
+	t.remainder = 0
+	t.targetTicks = int(d.Milliseconds()) * SIMULATION_TPS / 1000
+}
+
+// Changes the duration of the timer, keeping how many ticks it has counted
+func (t *Timer) Resize(d time.Duration) {
+	t.targetTicks = int(d.Milliseconds()) * SIMULATION_TPS / 1000
+	t.currentTicks = min(t.currentTicks, t.targetTicks)
+}
+
+// Adds to the duration of the timer
+func (t *Timer) Extend(d time.Duration) {
+	t.targetTicks += int(d.Milliseconds()) * SIMULATION_TPS / 1000
+}
+
+func (t *Timer) GetRemainingSeconds() float64 {
+	return float64(t.targetTicks-t.currentTicks) / float64(SIMULATION_TPS)
+}
+
 func (t *Timer) IsReady() bool {
 	return t.currentTicks >= t.targetTicks
 }
 
+// Gets how far along the timer is, from 0 to 1
+func (t *Timer) GetProgress() float64 {
+	if t.targetTicks <= 0 {
+		return 1
+	}
+
+	return float64(t.currentTicks) / float64(t.targetTicks)
+}
+
 func (t *Timer) Reset() {
 	t.currentTicks = 0
 }
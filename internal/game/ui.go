@@ -3,7 +3,7 @@ package game
 import (
 	"bytes"
 	"go-game-space-shooter/internal/assets"
-	"image"
+	"go-game-space-shooter/internal/widget"
 	"image/color"
 	"math"
 	"os"
@@ -12,15 +12,15 @@ import (
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 const (
-	WINDOW_PADDING  = 40
-	BACKGROUND_SIZE = 256
-	BUTTON_MARGIN   = 20
+	WINDOW_PADDING          = 40
+	BACKGROUND_SIZE         = 256
+	BUTTON_MARGIN           = 20
+	DAMAGE_NUMBER_MAX_TICKS = 200
 )
 
 func NewUi(game *Game) *Ui {
@@ -40,7 +40,7 @@ func NewUi(game *Game) *Ui {
 		Size:   80,
 	}
 
-	return &Ui{
+	u := &Ui{
 		game: game,
 		background: &Background{
 			filename: "background",
@@ -49,17 +49,76 @@ func NewUi(game *Game) *Ui {
 			oDx:      1.0,
 			oDy:      1.0,
 		},
-		mainMenuButtons:   []Button{},
-		pausedMenuButtons: []Button{},
-		deathMenuButtons:  []Button{},
-		font:              font,
-		fontBytes:         fontTrainOneRegularTTF,
+		theme: &widget.Theme{
+			Face:        font,
+			FontSize:    24,
+			TextColor:   color.RGBA{255, 255, 255, 255},
+			LabelColor:  color.RGBA{200, 200, 200, 255},
+			AccentColor: color.RGBA{10, 191, 245, 255},
+			LineColor:   color.RGBA{255, 255, 255, 255},
+		},
+		screen:    UiScreenNone,
+		font:      font,
+		fontBytes: fontTrainOneRegularTTF,
 	}
+
+	// Menus, built once and kept between updates
+	u.mainMenu = u.newMainMenu()
+	u.pauseMenu = u.newPauseMenu()
+	u.deathMenu = u.newDeathMenu()
+	u.settingsMenu = u.newSettingsMenu()
+	u.keyBindingsMenu = u.newKeyBindingsMenu()
+
+	return u
 }
 
 func (u *Ui) Update() error {
+	input := u.readWidgetInput()
+
+	// Settings & Leaderboard: Drawn on top of the menus, which don't take input meanwhile
+	if u.screen != UiScreenNone {
+		u.updateBackground()
+
+		if u.screen == UiScreenLeaderboard {
+			u.updateLeaderboard(input)
+		} else {
+			u.updateSettings(input)
+		}
+
+		return nil
+	}
+
+	// The focus is kept within a menu
+	if u.menuState != u.game.state {
+		u.menuState = u.game.state
+		if menu := u.getMenu(); menu != nil {
+			menu.ClearFocus()
+		}
+
+		// Leaderboard: Name the run if it placed
+		u.naming = false
+		if u.game.state == GameStateDeath && u.game.placement > 0 {
+			u.startNaming()
+		}
+	}
+
+	// Leaderboard: The name takes the keys, the menu & shortcuts wait for it
+	if u.naming {
+		u.updateBackground()
+		u.updateNaming()
+		u.forceCursorShape = -1
+		return nil
+	}
+
+	menuFocused := u.getMenu() != nil && u.getMenu().HasFocus()
+
+	// Gamepad: Pause when it's unplugged mid-game
+	if u.game.state == GameStatePlaying && u.game.actions.gamepads.IsJustDisconnected() {
+		u.game.state = GameStatePaused
+	}
+
 	// Pause/Unpause
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if u.game.actions.IsJustPressed(ActionPause) {
 		if u.game.state == GameStatePlaying {
 			u.game.state = GameStatePaused
 		} else if u.game.state == GameStatePaused {
@@ -67,26 +126,29 @@ func (u *Ui) Update() error {
 		}
 	}
 
-	// Start game
-	if u.game.state == GameStateInitial && inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	// Start game, unless a menu button is focused (which is pressed instead)
+	if u.game.state == GameStateInitial && !menuFocused && u.game.actions.IsJustPressed(ActionConfirm) {
 		u.game.state = GameStatePlaying
 	}
 
 	// Exit game
-	if (u.game.state == GameStateInitial || u.game.state == GameStateDeath) && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if (u.game.state == GameStateInitial || u.game.state == GameStateDeath) && u.game.actions.IsJustPressed(ActionBack) {
 		os.Exit(0)
 	}
 
 	// Restart game
-	if u.game.state == GameStateDeath && inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	if u.game.state == GameStateDeath && !menuFocused && u.game.actions.IsJustPressed(ActionConfirm) {
 		u.game.Restart()
 		u.game.state = GameStatePlaying
 	}
 
-	u.setMainMenuButtons()
-	u.setPauseMenuButtons()
-	u.setDeathMenuButtons()
-	u.checkButtonPresses()
+	u.updateBackground()
+
+	if menu := u.getMenu(); menu != nil {
+		u.updateMenu(menu, input)
+	} else {
+		u.forceCursorShape = -1
+	}
 
 	return nil
 }
@@ -106,6 +168,7 @@ func (u *Ui) Draw(screen *ebiten.Image) {
 	case GameStatePaused:
 		u.drawPauseScreen(screen)
 		u.drawEnemiesHpBar(screen)
+		u.drawBossHpBars(screen)
 		u.drawPlayerHpBar(screen)
 		u.drawDamageNumbers(screen)
 		u.drawCurrentWave(screen)
@@ -113,10 +176,12 @@ func (u *Ui) Draw(screen *ebiten.Image) {
 
 	case GameStatePlaying:
 		u.drawEnemiesHpBar(screen)
+		u.drawBossHpBars(screen)
 		u.drawPlayerHpBar(screen)
 		u.drawDamageNumbers(screen)
 		u.drawCurrentWave(screen)
 		u.drawPlayerStats(screen)
+		u.drawWeapons(screen)
 
 		cursorShape = ebiten.CursorShapeCrosshair
 	}
@@ -130,6 +195,19 @@ func (u *Ui) Draw(screen *ebiten.Image) {
 	if u.game.state != GameStateInitial {
 		u.drawScore(screen)
 	}
+
+	// Settings & Leaderboard: On top of the menus
+	if u.screen == UiScreenLeaderboard {
+		u.drawLeaderboard(screen)
+	} else if u.screen != UiScreenNone {
+		u.drawSettings(screen)
+	}
+
+	if u.game.configReloader != nil {
+		u.drawConfigReloadMessage(screen)
+	}
+
+	u.drawBindingsError(screen)
 }
 
 func (u *Ui) DrawBackground(screen *ebiten.Image) {
@@ -149,9 +227,12 @@ func (u *Ui) DrawBackground(screen *ebiten.Image) {
 			op.GeoM.Reset()
 		}
 	}
+}
 
+func (u *Ui) updateBackground() {
 	if slices.Contains([]GameState{GameStateInitial, GameStatePlaying, GameStateDeath}, u.game.state) {
-		u.background.ticker++
+		// Scrolls by the time of the update, so its speed doesn't depend on the TPS
+		u.background.ticker += u.game.clock.GetDelta() * SIMULATION_TPS
 
 		if u.background.ticker*u.background.oDx*u.background.velocity > BACKGROUND_SIZE {
 			u.background.ticker = 0
@@ -179,50 +260,9 @@ func (u *Ui) drawMainMenu(screen *ebiten.Image) {
 	text.Draw(screen, str, u.font, op)
 	op.GeoM.Reset()
 
-	// Draw Main Menu Buttons
-	if len(u.mainMenuButtons) > 0 {
-
-		minX, maxX := 0, 0
-		for _, button := range u.mainMenuButtons {
-			if button.collision.x1 > maxX {
-				minX = button.collision.x0
-				maxX = button.collision.x1
-			}
-		}
-
-		minX -= 10
-		maxX += 10
-
-		for i, button := range u.mainMenuButtons {
-			// Start button
-			u.font.Size = 24
-			op.ColorScale.Reset()
-
-			switch button.state {
-			case ButtonStateHover:
-				op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
-			default:
-				op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
-			}
-			op.PrimaryAlign = text.AlignCenter
-
-			_, textH = text.Measure(button.text, u.font, op.LineSpacing)
-
-			op.GeoM.Translate(button.position.x, button.position.y)
-			text.Draw(screen, button.text, u.font, op)
-			op.GeoM.Reset()
-
-			if i < len(u.mainMenuButtons)-1 {
-				vector.StrokeLine(screen, float32(minX), float32(button.collision.y0+int(textH))+BUTTON_MARGIN/2, float32(maxX), float32(button.collision.y0+int(textH))+BUTTON_MARGIN/2, 1.0, color.RGBA{255, 255, 255, 255}, true)
-			}
-
-			// Config: Draw Colission Rects
-			if Configs["DRAW_COLLISION_RECTS"] == "1" {
-				// Draw collision rectangle
-				vector.StrokeRect(screen, float32(button.collision.x0), float32(button.collision.y0), float32(button.collision.x1-button.collision.x0), float32(button.collision.y1-button.collision.y0), 1.0, color.RGBA{255, 255, 0, 255}, true)
-			}
-		}
-	}
+	// Draw Main Menu
+	u.layoutMenu(u.mainMenu)
+	u.mainMenu.Draw(screen, u.theme)
 }
 
 func (u *Ui) drawControls(screen *ebiten.Image) {
@@ -235,14 +275,12 @@ func (u *Ui) drawControls(screen *ebiten.Image) {
 	op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
 	op.PrimaryAlign = text.AlignStart
 
-	str :=
-		`Controls
-W: Up
-S: Down
-A: Left
-D: Right
-Space/Left Click: Shoot
-Escape: Pause/Unpause`
+	// From the key bindings, up to pausing (confirm & back are for the menus)
+	str := "Controls"
+	for action := range ActionPause + 1 {
+		str += "\n" + u.game.actions.GetBindingNames(action, "/") + ": " + GetActionLabel(action)
+	}
+	str += "\n1-9/Mouse Wheel: Switch weapon"
 
 	_, textH := text.Measure(str, u.font, op.LineSpacing)
 
@@ -284,48 +322,9 @@ func (u *Ui) drawPauseScreen(screen *ebiten.Image) {
 	text.Draw(screen, str, u.font, op)
 	op.GeoM.Reset()
 
-	// Draw Paused Menu Buttons
-	if len(u.pausedMenuButtons) > 0 {
-
-		minX, maxX := 0, 0
-		for _, button := range u.pausedMenuButtons {
-			if button.collision.x1 > maxX {
-				minX = button.collision.x0
-				maxX = button.collision.x1
-			}
-		}
-
-		minX -= 10
-		maxX += 10
-
-		for i, button := range u.pausedMenuButtons {
-			// Start button
-			u.font.Size = 24
-			op.ColorScale.Reset()
-
-			switch button.state {
-			case ButtonStateHover:
-				op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
-			default:
-				op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
-			}
-			op.PrimaryAlign = text.AlignCenter
-
-			op.GeoM.Translate(button.position.x, button.position.y)
-			text.Draw(screen, button.text, u.font, op)
-			op.GeoM.Reset()
-
-			if i < len(u.pausedMenuButtons)-1 {
-				vector.StrokeLine(screen, float32(minX), float32(button.collision.y0)-BUTTON_MARGIN/2, float32(maxX), float32(button.collision.y0)-BUTTON_MARGIN/2, 1.0, color.RGBA{255, 255, 255, 255}, true)
-			}
-
-			// Config: Draw Colission Rects
-			if Configs["DRAW_COLLISION_RECTS"] == "1" {
-				// Draw collision rectangle
-				vector.StrokeRect(screen, float32(button.collision.x0), float32(button.collision.y0), float32(button.collision.x1-button.collision.x0), float32(button.collision.y1-button.collision.y0), 1.0, color.RGBA{255, 255, 0, 255}, true)
-			}
-		}
-	}
+	// Draw Paused Menu
+	u.layoutMenu(u.pauseMenu)
+	u.pauseMenu.Draw(screen, u.theme)
 }
 
 func (u *Ui) drawDeathScreen(screen *ebiten.Image) {
@@ -360,48 +359,15 @@ func (u *Ui) drawDeathScreen(screen *ebiten.Image) {
 	text.Draw(screen, str, u.font, op)
 	op.GeoM.Reset()
 
-	// Draw Death Menu Buttons
-	if len(u.deathMenuButtons) > 0 {
-
-		minX, maxX := 0, 0
-		for _, button := range u.deathMenuButtons {
-			if button.collision.x1 > maxX {
-				minX = button.collision.x0
-				maxX = button.collision.x1
-			}
-		}
-
-		minX -= 10
-		maxX += 10
-
-		for i, button := range u.deathMenuButtons {
-			// Start button
-			u.font.Size = 24
-			op.ColorScale.Reset()
-
-			switch button.state {
-			case ButtonStateHover:
-				op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
-			default:
-				op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
-			}
-			op.PrimaryAlign = text.AlignCenter
-
-			op.GeoM.Translate(button.position.x, button.position.y)
-			text.Draw(screen, button.text, u.font, op)
-			op.GeoM.Reset()
-
-			if i < len(u.deathMenuButtons)-1 {
-				vector.StrokeLine(screen, float32(minX), float32(button.collision.y0)-BUTTON_MARGIN/2, float32(maxX), float32(button.collision.y0)-BUTTON_MARGIN/2, 1.0, color.RGBA{255, 255, 255, 255}, true)
-			}
-
-			// Config: Draw Colission Rects
-			if Configs["DRAW_COLLISION_RECTS"] == "1" {
-				// Draw collision rectangle
-				vector.StrokeRect(screen, float32(button.collision.x0), float32(button.collision.y0), float32(button.collision.x1-button.collision.x0), float32(button.collision.y1-button.collision.y0), 1.0, color.RGBA{255, 255, 0, 255}, true)
-			}
-		}
+	// Leaderboard: Where the run placed, and its name while it's entered
+	u.drawPlacement(screen)
+	if u.naming {
+		return
 	}
+
+	// Draw Death Menu
+	u.layoutMenu(u.deathMenu)
+	u.deathMenu.Draw(screen, u.theme)
 }
 
 func (u *Ui) drawPlayerHpBar(screen *ebiten.Image) {
@@ -411,11 +377,11 @@ func (u *Ui) drawPlayerHpBar(screen *ebiten.Image) {
 	u.font.Size = 20
 	op.ColorScale.Reset()
 
-	if u.game.player.character.hp.current <= u.game.player.character.hp.max*0.25 {
+	if u.game.player.health.current <= u.game.player.health.max*0.25 {
 		// Low Health
 		op.ColorScale.Scale(255/255.0, 0/255.0, 0/255.0, 255/255.0)
 
-	} else if u.game.player.character.hp.current <= u.game.player.character.hp.max*0.5 {
+	} else if u.game.player.health.current <= u.game.player.health.max*0.5 {
 		// Median Health
 		op.ColorScale.Scale(255/255.0, 255/255.0, 0/255.0, 255/255.0)
 
@@ -427,8 +393,8 @@ func (u *Ui) drawPlayerHpBar(screen *ebiten.Image) {
 	op.PrimaryAlign = text.AlignStart
 
 	strs := []string{
-		strconv.FormatFloat(u.game.player.character.hp.current, 'f', -1, 64),
-		strconv.FormatFloat(u.game.player.character.hp.max, 'f', -1, 64),
+		strconv.FormatFloat(u.game.player.health.current, 'f', -1, 64),
+		strconv.FormatFloat(u.game.player.health.max, 'f', -1, 64),
 	}
 	str := "HP: " + strings.Join(strs, "/")
 
@@ -439,6 +405,52 @@ func (u *Ui) drawPlayerHpBar(screen *ebiten.Image) {
 	op.GeoM.Reset()
 }
 
+// Draws the outcome of the last configs reload above the weapons, errors stay until the configs are valid again
+func (u *Ui) drawConfigReloadMessage(screen *ebiten.Image) {
+
+	str, isError := u.game.configReloader.GetMessage()
+	if str == "" {
+		return
+	}
+
+	_, wsY := GetWindowSize()
+	u.drawMessage(screen, str, isError, wsY-WINDOW_PADDING-60)
+}
+
+// Draws why the bindings file couldn't be loaded above the configs reload message, until the bindings are saved again
+func (u *Ui) drawBindingsError(screen *ebiten.Image) {
+
+	if u.game.bindingsErr == nil {
+		return
+	}
+
+	_, wsY := GetWindowSize()
+	u.drawMessage(screen, "Key bindings not loaded, using the defaults: "+u.game.bindingsErr.Error(), true, wsY-WINDOW_PADDING-80)
+}
+
+// Draws a message centered at the given height, in red if it's an error
+func (u *Ui) drawMessage(screen *ebiten.Image, str string, isError bool, y float64) {
+
+	wsX, _ := GetWindowSize()
+
+	op := &text.DrawOptions{}
+	op.LineSpacing = 20
+	u.font.Size = 16
+	op.ColorScale.Reset()
+
+	if isError {
+		op.ColorScale.Scale(255/255.0, 80/255.0, 80/255.0, 255/255.0)
+	} else {
+		op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255/255.0)
+	}
+
+	op.PrimaryAlign = text.AlignCenter
+
+	op.GeoM.Translate(wsX/2, y)
+	text.Draw(screen, str, u.font, op)
+	op.GeoM.Reset()
+}
+
 func (u *Ui) drawCurrentWave(screen *ebiten.Image) {
 	_, wsY := GetWindowSize()
 
@@ -475,6 +487,31 @@ func (u *Ui) drawPlayerStats(screen *ebiten.Image) {
 		TrimTrailingZeros(strconv.FormatFloat(u.game.player.attack.criticalChance, 'f', 2, 64)) + "%",
 		"x" + TrimTrailingZeros(strconv.FormatFloat(u.game.player.attack.criticalModifier, 'f', 2, 64)),
 	}
+	labels := []string{
+		"Damage: ",
+		"Crit. Chance: ",
+		"Crit. Modifier: ",
+	}
+
+	// Projectile modifiers, once the player has any
+	modifiers := u.game.player.attack.modifiers
+	if modifiers.Homing > 0 {
+		strs = append(strs, TrimTrailingZeros(strconv.FormatFloat(modifiers.Homing, 'f', 2, 64)))
+		labels = append(labels, "Homing: ")
+	}
+	if modifiers.Pierce > 0 {
+		strs = append(strs, strconv.Itoa(modifiers.Pierce))
+		labels = append(labels, "Piercing: ")
+	}
+	if modifiers.Bounce > 0 {
+		strs = append(strs, strconv.Itoa(modifiers.Bounce))
+		labels = append(labels, "Bouncing: ")
+	}
+	if modifiers.Splash > 0 {
+		strs = append(strs, TrimTrailingZeros(strconv.FormatFloat(modifiers.Splash, 'f', 2, 64)))
+		labels = append(labels, "Splash: ")
+	}
+
 	str := strings.Join(strs, "\n")
 
 	textW, textH := text.Measure(str, u.font, op.LineSpacing)
@@ -489,33 +526,147 @@ func (u *Ui) drawPlayerStats(screen *ebiten.Image) {
 
 	op.PrimaryAlign = text.AlignEnd
 
-	strs = []string{
-		"Damage: ",
-		"Crit. Chance: ",
-		"Crit. Modifier: ",
-	}
-	str = strings.Join(strs, "\n")
+	str = strings.Join(labels, "\n")
 
 	op.GeoM.Translate(wsX-WINDOW_PADDING-textW, wsY-WINDOW_PADDING-textH)
 	text.Draw(screen, str, u.font, op)
 	op.GeoM.Reset()
+
+	// Active status effects, above the stats
+	u.drawStatusEffects(screen, wsX-WINDOW_PADDING, wsY-WINDOW_PADDING-textH-30)
+}
+
+// Draws the icons of the active status effects, with their countdowns, right-aligned to posX
+func (u *Ui) drawStatusEffects(screen *ebiten.Image, posX float64, posY float64) {
+
+	const SIZE float64 = 36.0
+	const GAP float64 = 10.0
+
+	effects := u.game.player.effects.GetEffects()
+
+	op := &text.DrawOptions{}
+	op.PrimaryAlign = text.AlignCenter
+
+	for i := len(effects) - 1; i >= 0; i-- {
+		effect := effects[i]
+		posX -= SIZE
+
+		iconY := posY - SIZE
+
+		// Icon
+		vector.DrawFilledRect(screen, float32(posX), float32(iconY), float32(SIZE), float32(SIZE), effect.GetColor(), true)
+
+		// Debuffs are outlined in red
+		if effect.IsDebuff() {
+			vector.StrokeRect(screen, float32(posX), float32(iconY), float32(SIZE), float32(SIZE), 2.0, color.RGBA{255, 0, 0, 255}, true)
+		}
+
+		u.font.Size = 16
+		op.ColorScale.Reset()
+		op.ColorScale.Scale(0, 0, 0, 1)
+
+		op.GeoM.Translate(posX+SIZE/2, iconY+SIZE/2-10)
+		text.Draw(screen, effect.GetLabel(), u.font, op)
+		op.GeoM.Reset()
+
+		// Stacks
+		if effect.GetStacks() > 1 {
+			u.font.Size = 12
+			op.ColorScale.Reset()
+
+			op.GeoM.Translate(posX+SIZE, iconY-14)
+			text.Draw(screen, "x"+strconv.Itoa(effect.GetStacks()), u.font, op)
+			op.GeoM.Reset()
+		}
+
+		// Countdown
+		u.font.Size = 12
+		op.ColorScale.Reset()
+
+		op.GeoM.Translate(posX+SIZE/2, posY+2)
+		text.Draw(screen, strconv.FormatFloat(effect.GetRemainingSeconds(), 'f', 1, 64)+"s", u.font, op)
+		op.GeoM.Reset()
+
+		posX -= GAP
+	}
+}
+
+func (u *Ui) drawWeapons(screen *ebiten.Image) {
+	wsX, wsY := GetWindowSize()
+
+	op := &text.DrawOptions{}
+	u.font.Size = 16
+	op.PrimaryAlign = text.AlignCenter
+
+	var strs []string
+	for slot, weapon := range u.game.player.weapons {
+		if weapon.IsUnlocked() {
+			strs = append(strs, strconv.Itoa(slot+1)+" "+strings.ToUpper(weapon.GetName()))
+		}
+	}
+
+	const GAP float64 = 30.0
+
+	// Measure the slots, to center them
+	widths := make([]float64, len(strs))
+	totalW := GAP * float64(len(strs)-1)
+	for i, str := range strs {
+		widths[i], _ = text.Measure(str, u.font, op.LineSpacing)
+		totalW += widths[i]
+	}
+
+	posX := wsX/2.0 - totalW/2.0
+	posY := wsY - WINDOW_PADDING - 20
+
+	i := 0
+	for _, weapon := range u.game.player.weapons {
+		if !weapon.IsUnlocked() {
+			continue
+		}
+
+		op.ColorScale.Reset()
+		if weapon == u.game.player.GetWeapon() {
+			// Active weapon
+			op.ColorScale.Scale(10/255.0, 191/255.0, 245/255.0, 255/255.0)
+		} else {
+			op.ColorScale.Scale(150/255.0, 150/255.0, 150/255.0, 255/255.0)
+		}
+
+		op.GeoM.Translate(posX+widths[i]/2.0, posY)
+		text.Draw(screen, strs[i], u.font, op)
+		op.GeoM.Reset()
+
+		// Charge bar, under the active weapon
+		if charge := weapon.GetCharge(); charge > 0 {
+			vector.DrawFilledRect(screen, float32(posX), float32(posY+22), float32(widths[i]*charge), 4, color.RGBA{10, 191, 245, 255}, true)
+		}
+
+		posX += widths[i] + GAP
+		i++
+	}
 }
 
 func (u *Ui) drawEnemiesHpBar(screen *ebiten.Image) {
 
+	alpha := u.game.getDrawAlpha()
+
 	// Loop through enemies
 	if len(u.game.enemies) > 0 {
 		for _, enemy := range u.game.enemies {
-			if enemy.character.position.collision != nil && !enemy.disabled {
+			// Bosses have a dedicated HP bar
+			if enemy.collider.ready && !enemy.disabled && enemy.encounter == nil {
 
-				posX := float32(enemy.character.position.collision.x0)
-				posY := float32(enemy.character.position.collision.y0 - 20)
-				posW := float32(enemy.character.position.collision.x1 - enemy.character.position.collision.x0)
+				// Above the enemy, where its sprite is drawn
+				offsetX, offsetY := enemy.getInterpolatedOffset(alpha)
+
+				posX := float32(float64(enemy.collider.GetBounds().x0) + offsetX)
+				posY := float32(float64(enemy.collider.GetBounds().y0-20) + offsetY)
+				posW := float32(enemy.collider.GetBounds().x1 - enemy.collider.GetBounds().x0)
 				posH := float32(8.0)
 
 				vector.StrokeRect(screen, posX, posY, posW, posH, 1.0, color.RGBA{255, 255, 255, 1}, true)
 
-				posW *= float32(enemy.character.hp.current*100/enemy.character.hp.max) / 100
+				posW *= float32(enemy.health.current*100/enemy.health.max) / 100
 
 				vector.DrawFilledRect(screen, posX+1, posY+1, posW-1, posH-1, color.RGBA{255, 0, 0, 1}, true)
 			}
@@ -523,6 +674,46 @@ func (u *Ui) drawEnemiesHpBar(screen *ebiten.Image) {
 	}
 }
 
+func (u *Ui) drawBossHpBars(screen *ebiten.Image) {
+	wsX, _ := GetWindowSize()
+
+	posW := float32(wsX * 0.5)
+	posH := float32(16.0)
+	posX := float32(wsX)/2 - posW/2
+	posY := float32(150.0)
+
+	for _, enemy := range u.game.enemies {
+		if enemy.encounter == nil || enemy.disabled {
+			continue
+		}
+
+		op := &text.DrawOptions{}
+		u.font.Size = 20
+		op.ColorScale.Reset()
+		op.ColorScale.Scale(255/255.0, 60/255.0, 0/255.0, 255/255.0)
+		op.PrimaryAlign = text.AlignCenter
+
+		str := strings.ToUpper(enemy.enemyType) + " - PHASE " + strconv.Itoa(enemy.encounter.GetPhase()+1)
+
+		_, textH := text.Measure(str, u.font, op.LineSpacing)
+
+		op.GeoM.Translate(float64(wsX)/2.0, float64(posY)-textH-4)
+		text.Draw(screen, str, u.font, op)
+		op.GeoM.Reset()
+
+		vector.StrokeRect(screen, posX, posY, posW, posH, 1.0, color.RGBA{255, 255, 255, 255}, true)
+		vector.DrawFilledRect(screen, posX+1, posY+1, (posW-2)*float32(enemy.health.current/enemy.health.max), posH-2, color.RGBA{255, 0, 0, 255}, true)
+
+		// Phase thresholds
+		for _, phase := range enemy.encounter.phases[1:] {
+			markerX := posX + posW*float32(phase.Threshold)
+			vector.StrokeLine(screen, markerX, posY-3, markerX, posY+posH+3, 2.0, color.RGBA{255, 255, 255, 255}, true)
+		}
+
+		posY += posH + 40
+	}
+}
+
 func (u *Ui) drawScore(screen *ebiten.Image) {
 
 	wsX, _ := GetWindowSize()
@@ -556,11 +747,14 @@ func (u *Ui) drawScore(screen *ebiten.Image) {
 // Draw Damage Numbers
 func (u *Ui) drawDamageNumbers(screen *ebiten.Image) {
 
-	const MAX_TICKS = 200
+	// Config: Draw Damage Numbers
+	if !Configs.DrawDamageNumbers {
+		return
+	}
+
+	interpolation := u.game.getDrawAlpha()
 
 	if len(u.game.damageNumbers) > 0 {
-		var newDamageNumbers []DamageNumber
-
 		for _, damageNumber := range u.game.damageNumbers {
 
 			op := &text.DrawOptions{}
@@ -577,7 +771,7 @@ func (u *Ui) drawDamageNumbers(screen *ebiten.Image) {
 				op.ColorScale.Scale(255/255.0, 255/255.0, 255/255.0, 255.0)
 			}
 
-			alpha := float32(MAX_TICKS-damageNumber.ticksPassed) * 1 / 100
+			alpha := float32(DAMAGE_NUMBER_MAX_TICKS-damageNumber.ticksPassed) * 1 / 100
 			if alpha < 0 {
 				alpha = 0
 			} else if alpha > 1 {
@@ -590,266 +784,117 @@ func (u *Ui) drawDamageNumbers(screen *ebiten.Image) {
 
 			str := TrimTrailingZeros(strconv.FormatFloat(damageNumber.damage, 'f', 2, 64))
 
-			op.GeoM.Translate(damageNumber.x, damageNumber.y-float64(damageNumber.ticksPassed))
+			// Rises a pixel every tick, between the last two ticks like the sprites
+			rise := math.Max(0, float64(damageNumber.ticksPassed)-1+interpolation)
+
+			op.GeoM.Translate(damageNumber.x, damageNumber.y-rise)
 			text.Draw(screen, str, u.font, op)
 			op.GeoM.Reset()
-
-			// Live for only 100 ticks
-			if damageNumber.ticksPassed <= MAX_TICKS {
-
-				if u.game.state == GameStatePlaying {
-					damageNumber.ticksPassed += 1
-				}
-
-				newDamageNumbers = append(newDamageNumbers, damageNumber)
-			}
 		}
-
-		u.game.damageNumbers = newDamageNumbers
 	}
 }
 
-// Set the Main Menu button list
-func (u *Ui) setMainMenuButtons() {
+// Creates the main menu, under the title
+func (u *Ui) newMainMenu() *widget.Panel {
+	panel := widget.NewPanel(newMenuList(
+		widget.NewButton("Start", func() {
+			u.game.state = GameStatePlaying
+		}),
+		widget.NewButton("Leaderboard", u.openLeaderboard),
+		widget.NewButton("Settings", u.openSettings),
+		widget.NewButton("Quit Game", func() {
+			os.Exit(0)
+		}),
+	))
+	panel.AnchorY = 0
+
+	return panel
+}
 
-	wsX, wsY := GetWindowSize()
-	var buttonList []Button
-
-	btn := Button{
-		text: "Start",
-		tag:  "start",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY * 0.4,
-		},
-	}
-	u.font.Size = 24
-	textW, textH := text.Measure(btn.text, u.font, u.font.Size)
-	x0, y0, x1, y1 := GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
-
-	/*
-		btn = Button{
-			text: "Settings",
-			tag:  "settings",
-			position: &Vector{
-				x: wsX / 2.0,
-				y: wsY*0.4 + (textH+BUTTON_MARGIN)*1,
-			},
-		}
-		u.font.Size = 24
-		textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-		x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-		btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-		buttonList = append(buttonList, btn)
-	*/
-
-	btn = Button{
-		text: "Quit Game",
-		tag:  "quit",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY*0.4 + (textH+BUTTON_MARGIN)*1,
-		},
-	}
-	u.font.Size = 24
-	textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-	x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
+// Creates the paused menu, at the bottom of the window
+func (u *Ui) newPauseMenu() *widget.Panel {
+	panel := widget.NewPanel(newMenuList(
+		widget.NewButton("Go to Main Menu", func() {
+			u.game.Restart()
+			u.game.state = GameStateInitial
+		}),
+		widget.NewButton("Settings", u.openSettings),
+		widget.NewButton("Quit Game", func() {
+			os.Exit(0)
+		}),
+	))
+	panel.AnchorY = 1
+	panel.Padding = WINDOW_PADDING
+
+	return panel
+}
 
-	u.mainMenuButtons = buttonList
+// Creates the death menu, at the bottom of the window
+func (u *Ui) newDeathMenu() *widget.Panel {
+	panel := widget.NewPanel(newMenuList(
+		widget.NewButton("Restart", func() {
+			u.game.Restart()
+		}),
+		widget.NewButton("Go to Main Menu", func() {
+			u.game.Restart()
+			u.game.state = GameStateInitial
+		}),
+		widget.NewButton("Quit Game", func() {
+			os.Exit(0)
+		}),
+	))
+	panel.AnchorY = 1
+	panel.Padding = WINDOW_PADDING
+
+	return panel
 }
 
-// Set the Paused Menu button list
-func (u *Ui) setPauseMenuButtons() {
+// Menu buttons, from top to bottom with a line between them
+func newMenuList(buttons ...widget.Widget) *widget.List {
+	list := widget.NewList(BUTTON_MARGIN, buttons...)
+	list.Separators = true
 
-	wsX, wsY := GetWindowSize()
-	var buttonList []Button
-
-	btn := Button{
-		text: "Quit Game",
-		tag:  "quit",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY - WINDOW_PADDING,
-		},
-	}
-	u.font.Size = 24
-	textW, textH := text.Measure(btn.text, u.font, u.font.Size)
-	btn.position.y -= (textH + BUTTON_MARGIN)
-	x0, y0, x1, y1 := GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
-
-	/*
-		btn = Button{
-			text: "Settings",
-			tag:  "settings",
-			position: &Vector{
-				x: wsX / 2.0,
-				y: wsY - WINDOW_PADDING - (textH+BUTTON_MARGIN)*2,
-			},
-		}
-		u.font.Size = 24
-		textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-		x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-		btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-		buttonList = append(buttonList, btn)
-	*/
-
-	btn = Button{
-		text: "Go to Main Menu",
-		tag:  "go_main_menu",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY - WINDOW_PADDING - (textH+BUTTON_MARGIN)*2,
-		},
+	return list
+}
+
+// Gets the menu of the game state, nil if there's none (while playing)
+func (u *Ui) getMenu() *widget.Panel {
+	switch u.game.state {
+	case GameStateInitial:
+		return u.mainMenu
+	case GameStatePaused:
+		return u.pauseMenu
+	case GameStateDeath:
+		return u.deathMenu
 	}
-	u.font.Size = 24
-	textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-	x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
 
-	u.pausedMenuButtons = buttonList
+	return nil
 }
 
-// Set the Death Menu button list
-func (u *Ui) setDeathMenuButtons() {
-
+// Lays out a menu for the window size: the main menu fills the window under the title, the others all of it
+func (u *Ui) layoutMenu(menu *widget.Panel) {
 	wsX, wsY := GetWindowSize()
-	var buttonList []Button
-
-	btn := Button{
-		text: "Quit Game",
-		tag:  "quit",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY - WINDOW_PADDING,
-		},
-	}
-	u.font.Size = 24
-	textW, textH := text.Measure(btn.text, u.font, u.font.Size)
-	btn.position.y -= (textH + BUTTON_MARGIN)
-	x0, y0, x1, y1 := GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
-
-	btn = Button{
-		text: "Go to Main Menu",
-		tag:  "go_main_menu",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY - WINDOW_PADDING - (textH+BUTTON_MARGIN)*2,
-		},
-	}
-	u.font.Size = 24
-	textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-	x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
-
-	btn = Button{
-		text: "Restart",
-		tag:  "restart",
-		position: &Vector{
-			x: wsX / 2.0,
-			y: wsY - WINDOW_PADDING - (textH+BUTTON_MARGIN)*3,
-		},
-	}
-	u.font.Size = 24
-	textW, textH = text.Measure(btn.text, u.font, u.font.Size)
-	x0, y0, x1, y1 = GetObjectRectCoords(btn.position.x, btn.position.y, textW, textH, 1, true, false)
-	btn.collision = &CollisionRect{x0: x0, y0: y0, x1: x1, y1: y1}
-	buttonList = append(buttonList, btn)
 
-	u.deathMenuButtons = buttonList
-}
+	// Config: Draw Collision Rects
+	u.theme.DrawBounds = Configs.DrawCollisionRects
 
-// Check button presses with menu buttons
-func (u *Ui) checkButtonPresses() {
-
-	cursor := GetCursorVector()
-	srcRect := image.Point{int(cursor.x), int(cursor.y)}
-	anyButtonHovered := false
-
-	// Check collisions with Main Menu Buttons
-	if u.game.state == GameStateInitial && len(u.mainMenuButtons) > 0 {
-		for i, button := range u.mainMenuButtons {
-			if srcRect.In(image.Rect(button.collision.x0, button.collision.y0, button.collision.x1, button.collision.y1)) {
-				anyButtonHovered = true
-
-				u.mainMenuButtons[i].state = ButtonStateHover
-
-				if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-					switch button.tag {
-					case "start":
-						u.game.state = GameStatePlaying
-					case "setting":
-						// TODO
-					case "quit":
-						os.Exit(0)
-					}
-				}
-			} else {
-				u.mainMenuButtons[i].state = ButtonStateDefault
-			}
-		}
+	posY := 0.0
+	if menu == u.mainMenu {
+		posY = wsY * 0.4
 	}
 
-	// Check collisions with Paused Menu Buttons
-	if u.game.state == GameStatePaused && len(u.pausedMenuButtons) > 0 {
-		for i, button := range u.pausedMenuButtons {
-			if srcRect.In(image.Rect(button.collision.x0, button.collision.y0, button.collision.x1, button.collision.y1)) {
-				anyButtonHovered = true
-
-				u.pausedMenuButtons[i].state = ButtonStateHover
-
-				if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-					switch button.tag {
-					case "go_main_menu":
-						u.game.Restart()
-						u.game.state = GameStateInitial
-					case "setting":
-						// TODO
-					case "quit":
-						os.Exit(0)
-					}
-				}
-			} else {
-				u.pausedMenuButtons[i].state = ButtonStateDefault
-			}
-		}
-	}
+	menu.Width = wsX
+	menu.Height = wsY - posY
+	menu.Layout(0, posY, u.theme)
+}
 
-	// Check collisions with Death Menu Buttons
-	if u.game.state == GameStateDeath && len(u.deathMenuButtons) > 0 {
-		for i, button := range u.deathMenuButtons {
-			if srcRect.In(image.Rect(button.collision.x0, button.collision.y0, button.collision.x1, button.collision.y1)) {
-				anyButtonHovered = true
-
-				u.deathMenuButtons[i].state = ButtonStateHover
-
-				if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-					switch button.tag {
-					case "restart":
-						u.game.Restart()
-					case "go_main_menu":
-						u.game.Restart()
-						u.game.state = GameStateInitial
-					case "quit":
-						os.Exit(0)
-					}
-				}
-			} else {
-				u.deathMenuButtons[i].state = ButtonStateDefault
-			}
-		}
-	}
+// Updates a menu with the input, the cursor points at the widgets it hovers
+func (u *Ui) updateMenu(menu *widget.Panel, input *widget.Input) {
+
+	u.layoutMenu(menu)
+	menu.Update(input)
 
-	if anyButtonHovered {
+	if menu.IsHovering() {
 		if ebiten.CursorShape() != ebiten.CursorShapePointer {
 			ebiten.SetCursorShape(ebiten.CursorShapePointer)
 		}
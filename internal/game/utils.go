@@ -26,9 +26,20 @@ func GetWindowSize() (float64, float64) {
 
 }
 
-func CheckWithinBounds(x float64, y float64, w float64, h float64, s float64) (float64, float64) {
+func NewArena(width float64, height float64) *Arena {
+	return &Arena{
+		width:  width,
+		height: height,
+	}
+}
+
+func (a *Arena) Size() (float64, float64) {
+	return a.width, a.height
+}
+
+func CheckWithinBounds(arena *Arena, x float64, y float64, w float64, h float64, s float64) (float64, float64) {
 
-	wsX, wsY := GetWindowSize()
+	wsX, wsY := arena.Size()
 
 	if x < w*s/2+WINDOW_PADDING {
 		x = w*s/2 + WINDOW_PADDING
@@ -102,8 +113,8 @@ func TrimTrailingZeros(str string) string {
 	return strings.TrimRight(strings.TrimRight(str, "0"), ".")
 }
 
-func GetRandomSpawnPosition(random *rand.Rand, offset int) (float64, float64) {
-	wsX, wsY := GetWindowSize()
+func GetRandomSpawnPosition(random *rand.Rand, arena *Arena, offset int) (float64, float64) {
+	wsX, wsY := arena.Size()
 
 	posYDir := random.Intn(2) // Generate an integer number between 0 and 1
 	posX := float64(random.Intn(int(wsX)))
@@ -115,3 +126,26 @@ func GetRandomSpawnPosition(random *rand.Rand, offset int) (float64, float64) {
 
 	return posX, posY
 }
+
+func GetEdgeSpawnPosition(random *rand.Rand, arena *Arena, edge string, offset int) (float64, float64) {
+	wsX, wsY := arena.Size()
+
+	// Any of the four edges
+	if edge == "any" {
+		edge = []string{"top", "bottom", "left", "right"}[random.Intn(4)]
+	}
+
+	switch edge {
+	case "top":
+		return float64(random.Intn(int(wsX))), float64(random.Intn(offset) - offset)
+	case "bottom":
+		return float64(random.Intn(int(wsX))), wsY + float64(offset+random.Intn(offset))
+	case "left":
+		return float64(random.Intn(offset) - offset), float64(random.Intn(int(wsY)))
+	case "right":
+		return wsX + float64(offset+random.Intn(offset)), float64(random.Intn(int(wsY)))
+	}
+
+	// Default: Either the top or bottom edge
+	return GetRandomSpawnPosition(random, arena, offset)
+}
@@ -0,0 +1,124 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var waveSpawnEdges = []string{"", "top", "bottom", "left", "right", "any"}
+
+func LoadCampaign(files fs.FS, path string) (*Campaign, error) {
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &Campaign{}
+
+	err = json.Unmarshal(data, campaign)
+	if err != nil {
+		return nil, errors.New("cannot decode waves with filename \"" + path + "\": " + err.Error())
+	}
+
+	err = campaign.validate()
+	if err != nil {
+		return nil, errors.New("invalid waves in filename \"" + path + "\": " + err.Error())
+	}
+
+	return campaign, nil
+}
+
+// Gets the scripted wave, or nil once the campaign has run out of waves
+func (c *Campaign) GetWave(wave int) *WaveDefinition {
+	if c == nil || wave < 1 || wave > len(c.Waves) {
+		return nil
+	}
+
+	return &c.Waves[wave-1]
+}
+
+func (c *Campaign) validate() error {
+	for i, wave := range c.Waves {
+		prefix := "wave " + strconv.Itoa(i+1) + ": "
+
+		if len(wave.Groups) == 0 {
+			return errors.New(prefix + "must have at least one group")
+		}
+
+		for _, group := range wave.Groups {
+			if group.Enemy == "" {
+				return errors.New(prefix + "enemy type cannot be empty")
+			}
+			if !slices.Contains(waveSpawnEdges, group.Edge) {
+				return errors.New(prefix + "unknown spawn edge \"" + group.Edge + "\"")
+			}
+			if group.Count < 1 {
+				return errors.New(prefix + "enemy count must be at least 1")
+			}
+			if group.Delay < 0 || group.Interval < 0 {
+				return errors.New(prefix + "delays cannot be negative")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Spawns the current wave: scripted by the campaign, or procedural once it runs out
+func (g *Game) spawnWave() {
+
+	wave := g.data.Campaign.GetWave(g.currentWave)
+	if wave == nil {
+		enemies := SpawnEnemies(g.random, g.arena, g.enemyTypes, g.enemies, g.currentWave, max_enemies_per_wave)
+		for _, enemy := range enemies[len(g.enemies):] {
+			g.addEnemy(enemy)
+		}
+		return
+	}
+
+	// Queue every enemy of each group, to be spawned after its delay
+	for i := range wave.Groups {
+		group := &wave.Groups[i]
+
+		for n := range group.Count {
+			delay := group.Delay + group.Interval*float64(n)
+
+			g.pendingSpawns = append(g.pendingSpawns, PendingSpawn{
+				group: group,
+				timer: NewTimer(time.Duration(delay * float64(time.Second))),
+			})
+		}
+	}
+}
+
+// Spawns the queued enemies whose delay has passed
+func (g *Game) updatePendingSpawns() {
+
+	const OFFSET int = 200
+
+	if len(g.pendingSpawns) > 0 {
+		var tmp []PendingSpawn
+
+		for _, pending := range g.pendingSpawns {
+			pending.timer.Update()
+
+			if !pending.timer.IsReady() {
+				tmp = append(tmp, pending)
+				continue
+			}
+
+			eX, eY := GetEdgeSpawnPosition(g.random, g.arena, pending.group.Edge, OFFSET)
+
+			enemy := NewEnemy(g.enemyTypes.Get(pending.group.Enemy), eX, eY, 0)
+			enemy.isBoss = enemy.isBoss || pending.group.Boss
+
+			g.addEnemy(enemy)
+		}
+
+		g.pendingSpawns = tmp
+	}
+}
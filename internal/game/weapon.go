@@ -0,0 +1,222 @@
+package game
+
+import (
+	"image/color"
+	"time"
+)
+
+// Extra damage of a fully charged shot, relative to the weapon's damage
+const WEAPON_CHARGE_DAMAGE_MODIFIER = 3.0
+
+// Weapons the player can carry, in slot order. Stats are modifiers of the player's attack configs
+var weaponTypes = []WeaponType{
+	{
+		name:       "laser",
+		spriteName: "laser_blue",
+		audioName:  "laser.wav",
+		fireRate:   1.0,
+		velocity:   1.0,
+		damage:     1.0,
+		unlockWave: 0,
+		emitter:    EmitterPattern{Pattern: "single"},
+	},
+	{
+		name:       "rapid",
+		spriteName: "pill_blue",
+		tint:       color.RGBA{120, 255, 120, 255},
+		audioName:  "rapid.wav",
+		fireRate:   2.5,
+		velocity:   1.2,
+		damage:     0.45,
+		unlockWave: 3,
+		emitter:    EmitterPattern{Pattern: "single"},
+	},
+	{
+		name:       "spread",
+		spriteName: "laser_blue",
+		tint:       color.RGBA{255, 230, 80, 255},
+		audioName:  "spread.wav",
+		fireRate:   0.5,
+		velocity:   1.0,
+		damage:     0.7,
+		unlockWave: 5,
+		emitter:    EmitterPattern{Pattern: "spread", Count: 5, Step: 10},
+	},
+	{
+		name:       "beam",
+		spriteName: "laser_blue",
+		tint:       color.RGBA{255, 120, 255, 255},
+		audioName:  "beam.wav",
+		fireRate:   0.6,
+		velocity:   2.0,
+		damage:     2.0,
+		chargeTime: 1.5,
+		unlockWave: 7,
+		emitter:    EmitterPattern{Pattern: "single"},
+		modifiers:  ProjectileModifiers{Pierce: 5},
+	},
+	{
+		name:       "missiles",
+		spriteName: "bolt_bronze",
+		audioName:  "missile.wav",
+		fireRate:   0.4,
+		velocity:   0.5,
+		damage:     2.0,
+		unlockWave: 9,
+		emitter:    EmitterPattern{Pattern: "burst", Count: 2, Delay: 0.15, SpeedFrom: 0.8, SpeedTo: 1.0},
+		modifiers:  ProjectileModifiers{Homing: 3, Splash: 80, Lifetime: 3},
+	},
+}
+
+// Creates the weapon inventory, on top of the player's attack. Weapons using the same sound share it
+func NewWeapons(base *Attack, sounds *Sounds) []*Weapon {
+
+	weapons := make([]*Weapon, 0, len(weaponTypes))
+
+	for i := range weaponTypes {
+		weaponType := &weaponTypes[i]
+
+		weapon := &Weapon{
+			weaponType: weaponType,
+			attack: &Attack{
+				spriteName:       weaponType.spriteName,
+				tint:             weaponType.tint,
+				fireRate:         base.fireRate * weaponType.fireRate,
+				velocity:         base.velocity * weaponType.velocity,
+				damage:           base.damage * weaponType.damage,
+				criticalChance:   base.criticalChance,
+				criticalModifier: base.criticalModifier,
+				modifiers:        base.modifiers.Combine(weaponType.modifiers),
+				audio:            sounds.attacks[weaponType.audioName],
+				hitAudio:         base.hitAudio,
+			},
+			unlocked: weaponType.unlockWave <= 0,
+		}
+
+		// Create attack timer & emitter
+		weapon.attack.timer = NewTimer(time.Millisecond * time.Duration(1.0/weapon.attack.fireRate*1000))
+		weapon.attack.emitter = NewEmitter(&weaponType.emitter)
+
+		// Create charge timer
+		if weaponType.chargeTime > 0 {
+			weapon.charge = NewTimer(secondsToDuration(weaponType.chargeTime))
+		}
+
+		weapons = append(weapons, weapon)
+	}
+
+	return weapons
+}
+
+// Pulls or releases the trigger, and gets if the weapon fires, and its damage modifier.
+// Charged weapons charge while the trigger is held, and fire once it's released
+func (w *Weapon) PullTrigger(fire bool) (bool, float64) {
+
+	if w.charge == nil {
+		return fire, 1.0
+	}
+
+	if fire {
+		w.charge.Update()
+		w.charging = true
+		return false, 1.0
+	}
+
+	if w.charging {
+		modifier := 1.0 + w.charge.GetProgress()*WEAPON_CHARGE_DAMAGE_MODIFIER
+
+		w.charging = false
+		w.charge.Reset()
+
+		return true, modifier
+	}
+
+	return false, 1.0
+}
+
+func (w *Weapon) GetName() string {
+	return w.weaponType.name
+}
+
+// Gets how far along the charge is, from 0 to 1, or 0 if the weapon isn't charging
+func (w *Weapon) GetCharge() float64 {
+	if w.charge == nil || !w.charging {
+		return 0
+	}
+
+	return w.charge.GetProgress()
+}
+
+func (w *Weapon) IsUnlocked() bool {
+	return w.unlocked
+}
+
+// Switches to the weapon in the slot (starting at 0), if it has been unlocked
+func (p *Player) SelectWeapon(slot int) {
+
+	if slot < 0 || slot >= len(p.weapons) || slot == p.weaponSlot || !p.weapons[slot].unlocked {
+		return
+	}
+
+	// Drop the charge & queued projectiles of the previous weapon
+	previous := p.weapons[p.weaponSlot]
+	previous.attack.emitter.Clear()
+	if previous.charge != nil {
+		previous.charging = false
+		previous.charge.Reset()
+	}
+
+	p.weaponSlot = slot
+	p.attack = p.weapons[slot].attack
+}
+
+// Switches to the next (or previous) unlocked weapon, wrapping around
+func (p *Player) CycleWeapon(direction int) {
+
+	slot := p.weaponSlot
+	for range p.weapons {
+		slot = (slot + direction + len(p.weapons)) % len(p.weapons)
+
+		if p.weapons[slot].unlocked {
+			p.SelectWeapon(slot)
+			return
+		}
+	}
+}
+
+func (p *Player) GetWeapon() *Weapon {
+	return p.weapons[p.weaponSlot]
+}
+
+// Unlocks the weapons available on the wave
+func (p *Player) UnlockWeapons(wave int) {
+	for _, weapon := range p.weapons {
+		if weapon.weaponType.unlockWave <= wave {
+			weapon.unlocked = true
+		}
+	}
+}
+
+// Unlocks the next locked weapon and switches to it, or returns false if all weapons are unlocked
+func (p *Player) UnlockNextWeapon() bool {
+	for slot, weapon := range p.weapons {
+		if !weapon.unlocked {
+			weapon.unlocked = true
+			p.SelectWeapon(slot)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Player) updateWeapon(g *Game) {
+
+	if g.input.weapon > 0 {
+		p.SelectWeapon(g.input.weapon - 1)
+	}
+
+	if g.input.scroll != 0 {
+		p.CycleWeapon(g.input.scroll)
+	}
+}
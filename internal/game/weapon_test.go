@@ -0,0 +1,34 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/assets"
+	"go-game-space-shooter/internal/audio"
+	"testing"
+)
+
+// Every weapon has its own sound, and a sprite (or tint) of its own, from the game's files
+func TestWeaponTypes(t *testing.T) {
+
+	looks := make(map[any]string)
+	sounds := make(map[string]string)
+
+	for _, weaponType := range weaponTypes {
+		if _, ok := assets.SpriteMap[weaponType.spriteName]; !ok {
+			t.Errorf("%s: sprite %s was not found in the sprite map", weaponType.name, weaponType.spriteName)
+		}
+		if !audio.Exists(weaponType.audioName) {
+			t.Errorf("%s: audio %s was not found", weaponType.name, weaponType.audioName)
+		}
+
+		look := [2]any{weaponType.spriteName, weaponType.tint}
+		if other, ok := looks[look]; ok {
+			t.Errorf("%s looks the same as %s", weaponType.name, other)
+		}
+		looks[look] = weaponType.name
+
+		if other, ok := sounds[weaponType.audioName]; ok {
+			t.Errorf("%s sounds the same as %s", weaponType.name, other)
+		}
+		sounds[weaponType.audioName] = weaponType.name
+	}
+}
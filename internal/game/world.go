@@ -0,0 +1,253 @@
+package game
+
+import (
+	"go-game-space-shooter/internal/assets"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Sprite layers, drawn from the lowest to the highest
+const (
+	SPRITE_LAYER_PROJECTILES = iota
+	SPRITE_LAYER_PLAYER
+	SPRITE_LAYER_ENEMIES
+	SPRITE_LAYER_PICKUPS
+	SPRITE_LAYER_COUNT
+)
+
+func NewWorld() *World {
+	return &World{
+		entities: nil,
+		nextId:   0,
+	}
+}
+
+func (g *Game) setPlayer(player *Player) {
+	g.player = player
+	g.world.Spawn(player.Entity)
+}
+
+func (g *Game) addEnemy(enemy *Enemy) {
+	enemy.attack.hitAudio = g.sounds.enemyHit
+	g.world.Spawn(enemy.Entity)
+	g.enemies = append(g.enemies, enemy)
+}
+
+func (g *Game) addProjectile(projectile *Projectile) {
+	g.world.Spawn(projectile.Entity)
+	g.projectiles = append(g.projectiles, projectile)
+}
+
+func (g *Game) addPickup(pickup *Pickup) {
+	g.world.Spawn(pickup.Entity)
+	g.pickups = append(g.pickups, pickup)
+}
+
+func NewSpriteRenderer(spriteName string, layer int) *SpriteRenderer {
+	sprite, err := assets.NewSprite(spriteName)
+	if err != nil {
+		HandleError(err)
+	}
+
+	return &SpriteRenderer{
+		sprite: sprite,
+		layer:  layer,
+	}
+}
+
+func NewColliderComponent(clr color.RGBA) *ColliderComponent {
+	return &ColliderComponent{
+		shape: &CollisionShape{},
+		color: clr,
+		ready: false,
+	}
+}
+
+// Adds an entity to the world, its systems run on it until it's disabled
+func (w *World) Spawn(e *Entity) {
+	w.nextId++
+	e.id = w.nextId
+
+	// Place the collision shape where the entity spawns
+	w.UpdateCollider(e)
+
+	// Nothing to interpolate from yet
+	if e.transform != nil {
+		e.previous = *e.transform
+	}
+
+	w.entities = append(w.entities, e)
+}
+
+// Removes the disabled entities, keeping the order of the others
+func (w *World) RemoveDisabled() {
+	w.entities = slices.DeleteFunc(w.entities, func(e *Entity) bool {
+		return e.disabled
+	})
+}
+
+// Removes every entity, e.g. when the game restarts
+func (w *World) Clear() {
+	clear(w.entities)
+	w.entities = w.entities[:0]
+}
+
+// Keeps the transform of every entity, before the tick moves them
+func (w *World) SaveTransforms() {
+	for _, e := range w.entities {
+		if e.transform != nil {
+			e.previous = *e.transform
+		}
+	}
+}
+
+// AI system: Updates the entities with an AI, as many times as ticks (entities spawned meanwhile start on the next tick)
+func (w *World) UpdateAI(g *Game, ticks int) {
+
+	count := len(w.entities)
+
+	for range ticks {
+		for _, e := range w.entities[:count] {
+			if e.ai != nil && !e.disabled {
+				e.ai.Update(g)
+			}
+		}
+	}
+}
+
+// Movement system: Moves an entity along its direction, at its velocity
+func (w *World) Move(e *Entity) {
+
+	if e.transform == nil || e.movement == nil || e.direction == nil {
+		return
+	}
+
+	e.transform.x += e.direction.oDx * e.movement.velocity
+	e.transform.y += e.direction.oDy * e.movement.velocity
+}
+
+// Collision system: Places the collision shape of an entity where its sprite is drawn
+func (w *World) UpdateCollider(e *Entity) {
+
+	if e.transform == nil || e.renderer == nil || e.collider == nil {
+		return
+	}
+
+	SetSpriteShape(e.collider.shape, e.transform, e.renderer.sprite, e.transform.scale, e.transform.angle)
+	e.collider.ready = true
+}
+
+// Damage system: Removes HP from an entity and shows the damage number (with the effect), the entity is disabled once it has no HP left.
+// Returns false if the entity can't be damaged
+func (w *World) Damage(g *Game, e *Entity, damage float64, effect string) bool {
+
+	if e.disabled || e.health == nil {
+		return false
+	}
+
+	// Remove from the entity's HP
+	e.OffsetHp(-damage)
+
+	// Add to game's damage numbers
+	if e.collider != nil && e.collider.ready {
+		g.AddDamageNumber(damage, e.collider.GetBounds(), effect)
+	}
+
+	return true
+}
+
+// Render system: Draws the sprites of the entities, layer by layer, in the order they were spawned.
+// Sprites are drawn between their transform on the previous and the last tick, alpha being how far along the next tick is
+func (w *World) Draw(screen *ebiten.Image, alpha float64) {
+
+	for layer := range SPRITE_LAYER_COUNT {
+		for _, e := range w.entities {
+			if e.disabled || e.transform == nil || e.renderer == nil || e.renderer.hidden || e.renderer.layer != layer {
+				continue
+			}
+
+			transform := e.getInterpolatedTransform(alpha)
+			e.renderer.draw(screen, &transform)
+		}
+	}
+
+	// Config: Draw Colission Rects
+	if Configs.DrawCollisionRects {
+		for _, e := range w.entities {
+			if !e.disabled && e.collider != nil && e.collider.ready {
+				// Draw collision shape
+				e.collider.shape.Draw(screen, e.collider.color)
+			}
+		}
+	}
+}
+
+func (e *Entity) OffsetHp(offset float64) {
+
+	if e.health == nil {
+		return
+	}
+
+	e.health.current += offset
+
+	if e.health.current <= 0 {
+		e.health.current = 0
+		e.disabled = true
+
+	} else if e.health.current > e.health.max {
+		e.health.current = e.health.max
+	}
+}
+
+// Gets the transform between the previous and the last tick
+func (e *Entity) getInterpolatedTransform(alpha float64) Vector {
+	return Vector{
+		x:     e.previous.x + (e.transform.x-e.previous.x)*alpha,
+		y:     e.previous.y + (e.transform.y-e.previous.y)*alpha,
+		angle: e.previous.angle + math.Remainder(e.transform.angle-e.previous.angle, 360)*alpha, // Shortest turn
+		scale: e.previous.scale + (e.transform.scale-e.previous.scale)*alpha,
+	}
+}
+
+// Gets how far the interpolated transform is from the last tick's, so what's drawn around the sprite (e.g. HP bars) follows it
+func (e *Entity) getInterpolatedOffset(alpha float64) (float64, float64) {
+	transform := e.getInterpolatedTransform(alpha)
+	return transform.x - e.transform.x, transform.y - e.transform.y
+}
+
+// Counts down the lifetime of the entity (if it has one), and disables it once it's over
+func (e *Entity) updateLifetime() {
+
+	if e.lifetime == nil {
+		return
+	}
+
+	e.lifetime.Update()
+	if e.lifetime.IsReady() {
+		e.disabled = true
+	}
+}
+
+func (r *SpriteRenderer) draw(screen *ebiten.Image, transform *Vector) {
+
+	op := &ebiten.DrawImageOptions{}
+
+	r.sprite.Rotate(op, transform.angle)
+	r.sprite.Scale(op, transform.scale)
+	r.sprite.Translate(op, transform.scale, transform.x, transform.y)
+
+	// Sprites can be told apart by their tint
+	if r.tint != nil {
+		op.ColorScale.ScaleWithColor(r.tint)
+	}
+
+	screen.DrawImage(r.sprite.Image, op)
+}
+
+// Gets the bounding box of the collision shape
+func (c *ColliderComponent) GetBounds() *CollisionRect {
+	return c.shape.GetBounds()
+}
@@ -0,0 +1,45 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func NewButton(str string, onPress func()) *Button {
+	return &Button{Text: str, Align: AlignCenter, OnPress: onPress}
+}
+
+func (b *Button) Measure(theme *Theme) (float64, float64) {
+	w, h := text.Measure(b.Text, setFontSize(theme, b.Size), 0)
+	return max(w, b.MinWidth), h
+}
+
+func (b *Button) Layout(x float64, y float64, theme *Theme) {
+	w, h := b.Measure(theme)
+	b.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+}
+
+// Pressed with a click while hovered, or once focused
+func (b *Button) Update(input *Input) {
+
+	b.hovered = b.bounds.Contains(input.CursorX, input.CursorY)
+
+	if (b.hovered && input.Click) || (b.focused && input.Confirm) {
+		if b.OnPress != nil {
+			b.OnPress()
+		}
+	}
+}
+
+func (b *Button) Draw(screen *ebiten.Image, theme *Theme) {
+
+	face := setFontSize(theme, b.Size)
+	textW, _ := text.Measure(b.Text, face, 0)
+	drawText(screen, b.Text, face, alignX(b.bounds.X0, b.bounds.Width(), textW, b.Align), b.bounds.Y0, getStateColor(theme, &b.base, b.Active))
+
+	if b.focused {
+		drawFocusRing(screen, theme, b.bounds)
+	}
+
+	drawBounds(screen, theme, b.bounds)
+}
@@ -0,0 +1,36 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func NewLabel(str string) *Label {
+	return &Label{Text: str}
+}
+
+func (l *Label) Measure(theme *Theme) (float64, float64) {
+	w, h := text.Measure(l.Text, setFontSize(theme, l.Size), 0)
+	return max(w, l.MinWidth), h
+}
+
+func (l *Label) Layout(x float64, y float64, theme *Theme) {
+	w, h := l.Measure(theme)
+	l.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+}
+
+func (l *Label) Update(input *Input) {}
+
+func (l *Label) Draw(screen *ebiten.Image, theme *Theme) {
+
+	clr := theme.TextColor
+	if l.Color != nil {
+		clr = *l.Color
+	}
+
+	face := setFontSize(theme, l.Size)
+	textW, _ := text.Measure(l.Text, face, 0)
+	drawText(screen, l.Text, face, alignX(l.bounds.X0, l.bounds.Width(), textW, l.Align), l.bounds.Y0, clr)
+
+	drawBounds(screen, theme, l.bounds)
+}
@@ -0,0 +1,87 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+func NewList(spacing float64, children ...Widget) *List {
+	return &List{Children: children, Spacing: spacing, Align: AlignCenter}
+}
+
+// Lays out the children in a row, instead of a column
+func NewRow(spacing float64, children ...Widget) *List {
+	return &List{Children: children, Horizontal: true, Spacing: spacing, Align: AlignCenter}
+}
+
+func (l *List) Measure(theme *Theme) (float64, float64) {
+
+	var along, across float64
+
+	for i, child := range l.Children {
+		w, h := child.Measure(theme)
+		if l.Horizontal {
+			w, h = h, w
+		}
+
+		along += h
+		if i > 0 {
+			along += l.Spacing
+		}
+		across = max(across, w)
+	}
+
+	if l.Horizontal {
+		return along, across
+	}
+
+	return across, along
+}
+
+func (l *List) Layout(x float64, y float64, theme *Theme) {
+
+	w, h := l.Measure(theme)
+	l.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+
+	for _, child := range l.Children {
+		childW, childH := child.Measure(theme)
+
+		if l.Horizontal {
+			child.Layout(x, alignX(y, h, childH, l.Align), theme)
+			x += childW + l.Spacing
+		} else {
+			child.Layout(alignX(x, w, childW, l.Align), y, theme)
+			y += childH + l.Spacing
+		}
+	}
+}
+
+func (l *List) Update(input *Input) {
+	for _, child := range l.Children {
+		child.Update(input)
+	}
+}
+
+func (l *List) Draw(screen *ebiten.Image, theme *Theme) {
+
+	for i, child := range l.Children {
+		child.Draw(screen, theme)
+
+		// Separator: Halfway to the next child
+		if l.Separators && i < len(l.Children)-1 {
+			r := child.GetBounds()
+
+			if l.Horizontal {
+				x := float32(r.X1 + l.Spacing/2.0)
+				vector.StrokeLine(screen, x, float32(l.bounds.Y0-SEPARATOR_OVERHANG), x, float32(l.bounds.Y1+SEPARATOR_OVERHANG), 1.0, theme.LineColor, true)
+			} else {
+				y := float32(r.Y1 + l.Spacing/2.0)
+				vector.StrokeLine(screen, float32(l.bounds.X0-SEPARATOR_OVERHANG), y, float32(l.bounds.X1+SEPARATOR_OVERHANG), y, 1.0, theme.LineColor, true)
+			}
+		}
+	}
+}
+
+func (l *List) getChildren() []Widget {
+	return l.Children
+}
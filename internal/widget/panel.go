@@ -0,0 +1,102 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Creates a panel with its child in the middle
+func NewPanel(child Widget) *Panel {
+	return &Panel{Child: child, AnchorX: 0.5, AnchorY: 0.5, focus: -1}
+}
+
+func (p *Panel) Measure(theme *Theme) (float64, float64) {
+
+	w, h := p.Width, p.Height
+	if w == 0 || h == 0 {
+		childW, childH := p.Child.Measure(theme)
+
+		if w == 0 {
+			w = childW + p.Padding*2
+		}
+		if h == 0 {
+			h = childH + p.Padding*2
+		}
+	}
+
+	return w, h
+}
+
+func (p *Panel) Layout(x float64, y float64, theme *Theme) {
+
+	w, h := p.Measure(theme)
+	p.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+
+	childW, childH := p.Child.Measure(theme)
+	p.Child.Layout(
+		x+p.Padding+(w-p.Padding*2-childW)*p.AnchorX,
+		y+p.Padding+(h-p.Padding*2-childH)*p.AnchorY,
+		theme,
+	)
+}
+
+// Moves the focus (moving the mouse clears it), then updates the widgets in the panel
+func (p *Panel) Update(input *Input) {
+
+	widgets := collectFocusable(p.Child, nil)
+
+	if input.CursorMoved {
+		p.focus = -1
+	}
+
+	if input.Step != 0 && len(widgets) > 0 {
+		p.focus = moveFocus(p.focus, input.Step, len(widgets))
+	}
+
+	if p.focus >= len(widgets) {
+		p.focus = -1
+	}
+
+	for i, w := range widgets {
+		w.getBase().focused = i == p.focus
+	}
+
+	p.Child.Update(input)
+}
+
+func (p *Panel) Draw(screen *ebiten.Image, theme *Theme) {
+
+	if p.Background.A > 0 {
+		vector.DrawFilledRect(screen, float32(p.bounds.X0), float32(p.bounds.Y0), float32(p.bounds.Width()), float32(p.bounds.Height()), p.Background, true)
+	}
+
+	p.Child.Draw(screen, theme)
+}
+
+// Checks if the cursor hovers any widget in the panel
+func (p *Panel) IsHovering() bool {
+	for _, w := range collectFocusable(p.Child, nil) {
+		if w.getBase().hovered {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks if any widget in the panel is focused
+func (p *Panel) HasFocus() bool {
+	return p.focus != -1
+}
+
+func (p *Panel) ClearFocus() {
+	p.focus = -1
+
+	for _, w := range collectFocusable(p.Child, nil) {
+		w.getBase().focused = false
+	}
+}
+
+func (p *Panel) getChildren() []Widget {
+	return []Widget{p.Child}
+}
@@ -0,0 +1,97 @@
+package widget
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+func NewSlider(value float64, step float64, width float64, onChange func(value float64)) *Slider {
+	return &Slider{Value: value, Step: step, Width: width, OnChange: onChange}
+}
+
+func (s *Slider) Measure(theme *Theme) (float64, float64) {
+	textW, h := text.Measure(s.getText(), setFontSize(theme, 0), 0)
+	return max(s.Width+SLIDER_VALUE_GAP+textW, s.MinWidth), h
+}
+
+func (s *Slider) Layout(x float64, y float64, theme *Theme) {
+	w, h := s.Measure(theme)
+	s.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+}
+
+// Dragged from a click on the bar until the mouse button is released, or moved by a step with left & right once focused
+func (s *Slider) Update(input *Input) {
+
+	bar := s.getBar()
+
+	if s.dragged && !input.Hold {
+		s.dragged = false
+	}
+
+	if bar.Contains(input.CursorX, input.CursorY) && input.Click {
+		s.dragged = true
+	}
+
+	s.hovered = s.dragged || bar.Contains(input.CursorX, input.CursorY)
+
+	if s.dragged {
+		s.setValue((input.CursorX - bar.X0) / s.Width)
+	} else if s.focused && input.Change != 0 {
+		s.setValue(s.Value + float64(input.Change)*s.Step)
+	}
+}
+
+func (s *Slider) Draw(screen *ebiten.Image, theme *Theme) {
+
+	// An outline, filled up to the value
+	bar := s.getBar()
+	x0 := float32(bar.X0)
+	y0 := float32(bar.Y0) + 4
+	h := float32(bar.Height()) - 8
+	vector.DrawFilledRect(screen, x0, y0, float32(s.Width*s.Value), h, theme.AccentColor, true)
+	vector.StrokeRect(screen, x0, y0, float32(s.Width), h, 1.0, theme.LineColor, true)
+
+	drawText(screen, s.getText(), setFontSize(theme, 0), bar.X1+SLIDER_VALUE_GAP, s.bounds.Y0, getStateColor(theme, &s.base, false))
+
+	if s.focused {
+		drawFocusRing(screen, theme, s.bounds)
+	}
+
+	drawBounds(screen, theme, s.bounds)
+}
+
+// Sets the value, rounded to the step, and notifies the change
+func (s *Slider) setValue(value float64) {
+
+	value = math.Max(0, math.Min(1, value))
+	if s.Step > 0 {
+		value = math.Round(value/s.Step) * s.Step
+	}
+
+	if value == s.Value {
+		return
+	}
+
+	s.Value = value
+
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
+
+func (s *Slider) getBar() Rect {
+	return Rect{X0: s.bounds.X0, Y0: s.bounds.Y0, X1: s.bounds.X0 + s.Width, Y1: s.bounds.Y1}
+}
+
+// Gets the value shown next to the bar, as a percentage by default
+func (s *Slider) getText() string {
+	if s.Format != nil {
+		return s.Format(s.Value)
+	}
+
+	return strconv.Itoa(int(math.Round(s.Value*100))) + "%"
+}
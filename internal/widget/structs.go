@@ -0,0 +1,138 @@
+package widget
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Every widget is measured, placed, updated with the input and drawn.
+// Widgets are kept between updates (retained-mode), so their state (e.g. focus, dragging) carries on
+type Widget interface {
+	// Gets the width & height the widget takes
+	Measure(theme *Theme) (float64, float64)
+	// Places the widget with its top left corner at (x, y)
+	Layout(x float64, y float64, theme *Theme)
+	Update(input *Input)
+	Draw(screen *ebiten.Image, theme *Theme)
+	GetBounds() Rect
+}
+
+// Widgets which take the focus, and can be hovered
+type focusable interface {
+	Widget
+	getBase() *base
+}
+
+// Widgets which hold other widgets
+type container interface {
+	getChildren() []Widget
+}
+
+type Rect struct {
+	X0 float64
+	Y0 float64
+	X1 float64
+	Y1 float64
+}
+
+type Align int
+
+const (
+	AlignStart  Align = iota
+	AlignCenter Align = iota
+	AlignEnd    Align = iota
+)
+
+// How widgets are drawn
+type Theme struct {
+	Face        *text.GoTextFace // its size is set by each widget
+	FontSize    float64          // used by the widgets without a size of their own
+	TextColor   color.RGBA
+	LabelColor  color.RGBA
+	AccentColor color.RGBA // hovered & focused widgets
+	LineColor   color.RGBA
+	DrawBounds  bool // draw the bounds of every widget, for debugging purposes
+}
+
+// The input for a single update, read by the game (so it decides the keys & buttons)
+type Input struct {
+	CursorX     float64
+	CursorY     float64
+	CursorMoved bool // clears the focus, the mouse takes over
+	Click       bool // the left mouse button was just pressed
+	Hold        bool // the left mouse button is held down
+	Step        int  // moves the focus to the next (1) or previous (-1) widget
+	Change      int  // changes the focused widget up (1) or down (-1), e.g. a slider
+	Confirm     bool // presses the focused widget
+}
+
+// State shared by the widgets
+type base struct {
+	bounds  Rect
+	hovered bool
+	focused bool
+}
+
+type Label struct {
+	base
+	Text     string
+	Size     float64     // font size, the theme's if 0
+	Color    *color.RGBA // the theme's text color if nil
+	Align    Align       // within the minimum width
+	MinWidth float64
+}
+
+type Button struct {
+	base
+	Text     string
+	Size     float64 // font size, the theme's if 0
+	Align    Align   // within the minimum width
+	MinWidth float64
+	Active   bool // drawn as hovered, e.g. while it waits for input
+	OnPress  func()
+}
+
+// A value from 0 to 1, changed by dragging the bar or with left & right once focused
+type Slider struct {
+	base
+	Value    float64
+	Step     float64
+	Width    float64 // of the bar, the value is shown on its right
+	MinWidth float64
+	Format   func(value float64) string
+	OnChange func(value float64)
+	dragged  bool
+}
+
+// An "On" or "Off" value, changed by pressing it
+type Toggle struct {
+	base
+	Value    bool
+	MinWidth float64
+	OnChange func(value bool)
+}
+
+// Lays out its children one after another
+type List struct {
+	base
+	Children   []Widget
+	Horizontal bool
+	Spacing    float64
+	Align      Align // of the children across the list
+	Separators bool  // draws a line between the children
+}
+
+// The root of a screen: places its child within an area, and moves the focus between the widgets in it
+type Panel struct {
+	base
+	Child      Widget
+	Width      float64 // fits the child if 0
+	Height     float64 // fits the child if 0
+	AnchorX    float64 // where the child sits in the area, from 0 (left) to 1 (right)
+	AnchorY    float64 // from 0 (top) to 1 (bottom)
+	Padding    float64
+	Background color.RGBA // not drawn if transparent
+	focus      int        // the focused widget, in the order they're laid out (-1 for none)
+}
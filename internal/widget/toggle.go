@@ -0,0 +1,53 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func NewToggle(value bool, onChange func(value bool)) *Toggle {
+	return &Toggle{Value: value, OnChange: onChange}
+}
+
+func (t *Toggle) Measure(theme *Theme) (float64, float64) {
+	w, h := text.Measure(t.getText(), setFontSize(theme, 0), 0)
+	return max(w, t.MinWidth), h
+}
+
+func (t *Toggle) Layout(x float64, y float64, theme *Theme) {
+	w, h := t.Measure(theme)
+	t.bounds = Rect{X0: x, Y0: y, X1: x + w, Y1: y + h}
+}
+
+// Flipped with a click while hovered, or with confirm, left & right once focused
+func (t *Toggle) Update(input *Input) {
+
+	t.hovered = t.bounds.Contains(input.CursorX, input.CursorY)
+
+	if (t.hovered && input.Click) || (t.focused && (input.Confirm || input.Change != 0)) {
+		t.Value = !t.Value
+
+		if t.OnChange != nil {
+			t.OnChange(t.Value)
+		}
+	}
+}
+
+func (t *Toggle) Draw(screen *ebiten.Image, theme *Theme) {
+
+	drawText(screen, t.getText(), setFontSize(theme, 0), t.bounds.X0, t.bounds.Y0, getStateColor(theme, &t.base, false))
+
+	if t.focused {
+		drawFocusRing(screen, theme, t.bounds)
+	}
+
+	drawBounds(screen, theme, t.bounds)
+}
+
+func (t *Toggle) getText() string {
+	if t.Value {
+		return "On"
+	}
+
+	return "Off"
+}
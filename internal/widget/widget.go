@@ -0,0 +1,140 @@
+package widget
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Space between a focused widget and the ring around it
+const FOCUS_RING_PADDING = 6
+
+// How far the separators of a list go past its sides
+const SEPARATOR_OVERHANG = 10
+
+// Space between a slider bar and its value
+const SLIDER_VALUE_GAP = 20
+
+func (r Rect) Contains(x float64, y float64) bool {
+	return x >= r.X0 && x < r.X1 && y >= r.Y0 && y < r.Y1
+}
+
+func (r Rect) Width() float64 {
+	return r.X1 - r.X0
+}
+
+func (r Rect) Height() float64 {
+	return r.Y1 - r.Y0
+}
+
+func (b *base) GetBounds() Rect {
+	return b.bounds
+}
+
+func (b *base) IsHovered() bool {
+	return b.hovered
+}
+
+func (b *base) IsFocused() bool {
+	return b.focused
+}
+
+func (b *base) getBase() *base {
+	return b
+}
+
+// Sets the font size of the theme's face, or the theme's own size if 0
+func setFontSize(theme *Theme, size float64) *text.GoTextFace {
+	if size == 0 {
+		size = theme.FontSize
+	}
+
+	theme.Face.Size = size
+
+	return theme.Face
+}
+
+// Gets where content starts, aligned within a width
+func alignX(x float64, width float64, contentWidth float64, align Align) float64 {
+	switch align {
+	case AlignCenter:
+		return x + (width-contentWidth)/2.0
+	case AlignEnd:
+		return x + width - contentWidth
+	}
+
+	return x
+}
+
+// Gets the color of a widget, the accent color if it's hovered or focused
+func getStateColor(theme *Theme, b *base, active bool) color.RGBA {
+	if b.hovered || b.focused || active {
+		return theme.AccentColor
+	}
+
+	return theme.TextColor
+}
+
+func drawText(screen *ebiten.Image, str string, face *text.GoTextFace, x float64, y float64, clr color.RGBA) {
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(clr)
+	op.GeoM.Translate(x, y)
+	text.Draw(screen, str, face, op)
+}
+
+// Draws a ring around a focused widget, so it can be told apart from the one the cursor hovers
+func drawFocusRing(screen *ebiten.Image, theme *Theme, r Rect) {
+	vector.StrokeRect(screen, float32(r.X0-FOCUS_RING_PADDING), float32(r.Y0-FOCUS_RING_PADDING), float32(r.Width()+FOCUS_RING_PADDING*2), float32(r.Height()+FOCUS_RING_PADDING*2), 2.0, theme.AccentColor, true)
+}
+
+// Draws the bounds of a widget, if the theme asks for them
+func drawBounds(screen *ebiten.Image, theme *Theme, r Rect) {
+	if theme.DrawBounds {
+		vector.StrokeRect(screen, float32(r.X0), float32(r.Y0), float32(r.Width()), float32(r.Height()), 1.0, color.RGBA{255, 255, 0, 255}, true)
+	}
+}
+
+// Gets the focusable widgets in the order they're laid out, without going into other panels (which handle their own focus)
+func collectFocusable(w Widget, widgets []focusable) []focusable {
+
+	if f, ok := w.(focusable); ok && isFocusable(w) {
+		widgets = append(widgets, f)
+	}
+
+	if _, ok := w.(*Panel); ok {
+		return widgets
+	}
+
+	if c, ok := w.(container); ok {
+		for _, child := range c.getChildren() {
+			widgets = collectFocusable(child, widgets)
+		}
+	}
+
+	return widgets
+}
+
+func isFocusable(w Widget) bool {
+	switch w.(type) {
+	case *Button, *Slider, *Toggle:
+		return true
+	}
+
+	return false
+}
+
+// Moves a focus index by a step among the widgets, wrapping around at both ends.
+// With no focus (-1), the first step focuses the first widget, or the last one going backwards
+func moveFocus(i int, step int, count int) int {
+
+	if i < 0 || i >= count {
+		if step > 0 {
+			return 0
+		}
+		return count - 1
+	}
+
+	return ((i+step)%count + count) % count
+}
@@ -0,0 +1,135 @@
+package widget
+
+import "testing"
+
+func TestMoveFocus(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		focus    int
+		step     int
+		expected int
+	}{
+		{"first step forwards", -1, 1, 0},
+		{"first step backwards", -1, -1, 2},
+		{"next", 0, 1, 1},
+		{"previous", 2, -1, 1},
+		{"wraps around the end", 2, 1, 0},
+		{"wraps around the start", 0, -1, 2},
+		{"focus out of range", 5, 1, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			focus := moveFocus(c.focus, c.step, 3)
+			if focus != c.expected {
+				t.Errorf("expected the focus to move from %d to %d, got %d", c.focus, c.expected, focus)
+			}
+		})
+	}
+}
+
+// Only the buttons, sliders & toggles take the focus, in the order they're laid out (without going into nested panels)
+func TestPanelFocus(t *testing.T) {
+
+	pressed := 0
+	first := NewButton("First", func() { pressed++ })
+	slider := NewSlider(0.5, 0.1, 100, nil)
+	toggle := NewToggle(false, nil)
+	nested := NewPanel(NewButton("Nested", nil))
+
+	panel := NewPanel(NewList(0, NewLabel("Title"), first, NewRow(0, slider, toggle), nested))
+
+	// Down: The label is skipped
+	panel.Update(&Input{Step: 1})
+	if !first.IsFocused() || !panel.HasFocus() {
+		t.Fatalf("expected the first button to be focused")
+	}
+
+	panel.Update(&Input{Confirm: true})
+	if pressed != 1 {
+		t.Errorf("expected the focused button to be pressed once, got %d", pressed)
+	}
+
+	// Down twice: Wraps around from the toggle, the nested panel's button isn't reached
+	panel.Update(&Input{Step: 1})
+	panel.Update(&Input{Step: 1})
+	if !toggle.IsFocused() || slider.IsFocused() {
+		t.Fatalf("expected the toggle to be focused")
+	}
+
+	panel.Update(&Input{Step: 1})
+	if !first.IsFocused() || toggle.IsFocused() {
+		t.Errorf("expected the focus to wrap around to the first button")
+	}
+
+	// Up: Wraps around from the first button
+	panel.Update(&Input{Step: -1})
+	if !toggle.IsFocused() {
+		t.Errorf("expected the focus to wrap around to the toggle")
+	}
+
+	// Moving the mouse clears the focus
+	panel.Update(&Input{CursorMoved: true})
+	if panel.HasFocus() || toggle.IsFocused() {
+		t.Errorf("expected moving the cursor to clear the focus")
+	}
+
+	panel.Update(&Input{Step: 1})
+	panel.ClearFocus()
+	if panel.HasFocus() || first.IsFocused() {
+		t.Errorf("expected the focus to be cleared")
+	}
+
+	// The focus starts over once cleared
+	panel.Update(&Input{Step: -1})
+	if !toggle.IsFocused() {
+		t.Errorf("expected the first step up to focus the last widget")
+	}
+}
+
+// Focused sliders move by a step, rounded to it, and stay from 0 to 1
+func TestSliderStep(t *testing.T) {
+
+	var changes []float64
+	slider := NewSlider(0.5, 0.25, 100, func(value float64) {
+		changes = append(changes, value)
+	})
+	slider.focused = true
+
+	slider.Update(&Input{Change: 1})
+	if slider.Value != 0.75 {
+		t.Errorf("expected a step up to 0.75, got %v", slider.Value)
+	}
+
+	slider.Update(&Input{Change: 1})
+	slider.Update(&Input{Change: 1})
+	if slider.Value != 1 {
+		t.Errorf("expected the value to stop at 1, got %v", slider.Value)
+	}
+
+	// Unchanged values aren't notified
+	if len(changes) != 2 {
+		t.Errorf("expected 2 changes, got %v", changes)
+	}
+
+	for range 10 {
+		slider.Update(&Input{Change: -1})
+	}
+	if slider.Value != 0 {
+		t.Errorf("expected the value to stop at 0, got %v", slider.Value)
+	}
+
+	// Rounded to the step
+	slider.setValue(0.6)
+	if slider.Value != 0.5 {
+		t.Errorf("expected 0.6 to be rounded to 0.5, got %v", slider.Value)
+	}
+
+	// Sliders which aren't focused don't move
+	slider.focused = false
+	slider.Update(&Input{Change: 1})
+	if slider.Value != 0.5 {
+		t.Errorf("expected the value to stay at 0.5 without the focus, got %v", slider.Value)
+	}
+}